@@ -0,0 +1,513 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kuritka/12f/env"
+)
+
+const envPackage = "github.com/kuritka/12f/env"
+
+// options of env tag, which are supported by generated binders. Other options are rejected, so the generated
+// binder never silently differs from env.Bind
+var supportedOptions = map[string]bool{
+	"default": true, "require": true, "protected": true, "noprefix": true, "prefix": true,
+}
+
+// config contains command line arguments
+type config struct {
+	types     []string
+	prefix    string
+	separator string
+	output    string
+}
+
+// node is the field or nested structure bound by generated binder
+type node struct {
+	// path of the field within bound structure; e.g. Config.Credentials.KeyID
+	path string
+	// expr is Go expression of the field; e.g. c.Credentials.KeyID
+	expr string
+	typ  types.Type
+	// name of env variable and options of the tag of the field
+	name    string
+	options map[string]string
+	// defaulted is true if the structure of the field implements env.Defaulter
+	defaulted bool
+	// nested structure
+	nested    bool
+	pointer   bool
+	fields    []*node
+	defaulter bool
+	validator bool
+}
+
+// generator generates binders of the package
+type generator struct {
+	config
+	pkg     *types.Package
+	fset    *token.FileSet
+	imports map[string]string
+	// rolling contains structures which are being rolled; recursive structures are rejected
+	rolling map[types.Type]bool
+}
+
+// generate returns source code of binders of types c.types declared in package dir
+func generate(dir string, c config) (src []byte, err error) {
+	g := &generator{config: c, imports: map[string]string{}, rolling: map[types.Type]bool{}}
+	if g.separator == "" {
+		g.separator = "_"
+	}
+	g.prefix = strings.Trim(g.prefix, g.separator)
+	if err = g.load(dir); err != nil {
+		return
+	}
+	var body bytes.Buffer
+	for _, name := range c.types {
+		var root *node
+		if root, err = g.root(name); err != nil {
+			return
+		}
+		g.binder(&body, name, root)
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by 12f-gen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", g.pkg.Name())
+	g.imports["fmt"] = "fmt"
+	g.imports[envPackage] = "env"
+	// standard library is separated from other imports
+	for _, std := range []bool{true, false} {
+		if !std {
+			fmt.Fprintln(&b)
+		}
+		for _, path := range sortedKeys(g.imports) {
+			if std == !strings.Contains(strings.Split(path, "/")[0], ".") {
+				fmt.Fprintf(&b, "\t%q\n", path)
+			}
+		}
+	}
+	fmt.Fprintf(&b, ")\n%s", body.String())
+	if src, err = format.Source(b.Bytes()); err != nil {
+		return nil, fmt.Errorf("can't format generated code: %w", err)
+	}
+	return
+}
+
+// load parses and type-checks the package in dir. The output file is skipped, so stale binders don't break
+// the generation. Type errors are ignored, because the package may call binders which don't exist yet
+func (g *generator) load(dir string) (err error) {
+	var p *build.Package
+	var files []*ast.File
+	if p, err = build.ImportDir(dir, 0); err != nil {
+		return fmt.Errorf("can't load package %s: %w", dir, err)
+	}
+	g.fset = token.NewFileSet()
+	for _, name := range p.GoFiles {
+		path := filepath.Join(dir, name)
+		if g.output != "" && filepath.Clean(path) == filepath.Clean(g.output) {
+			continue
+		}
+		var f *ast.File
+		if f, err = parser.ParseFile(g.fset, path, nil, 0); err != nil {
+			return err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(g.fset, "source", nil), Error: func(error) {}}
+	g.pkg, _ = conf.Check(p.Name, g.fset, files, nil)
+	return nil
+}
+
+// root returns tree of nodes of the type declared in the package
+func (g *generator) root(name string) (root *node, err error) {
+	obj, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s is not declared in package %s", name, g.pkg.Name())
+	}
+	if _, ok = obj.Type().Underlying().(*types.Struct); !ok {
+		return nil, fmt.Errorf("type %s is not structure", name)
+	}
+	root = &node{path: name, expr: "c", typ: obj.Type(), nested: true}
+	err = g.roll(root, g.prefix)
+	return
+}
+
+// roll fills fields of nested structure n in order of declaration, the same way as env.Bind does
+func (g *generator) roll(n *node, prefix string) (err error) {
+	t := n.typ
+	if n.pointer {
+		t = t.(*types.Pointer).Elem()
+	}
+	g.rolling[t] = true
+	defer delete(g.rolling, t)
+	n.defaulter = hasMethod(t, "SetDefaults", false)
+	n.validator = hasMethod(t, "Validate", true)
+	s := t.Underlying().(*types.Struct)
+	for i := 0; i < s.NumFields(); i++ {
+		sf := s.Field(i)
+		raw := reflect.StructTag(s.Tag(i)).Get("env")
+		path := n.path + "." + sf.Name()
+		if raw == "-" {
+			continue
+		}
+		var names []string
+		var options map[string]string
+		if names, options, err = env.ParseTag(path, raw); err != nil {
+			return g.errorf(sf, "%w", err)
+		}
+		f := &node{path: path, expr: n.expr + "." + sf.Name(), typ: sf.Type(), options: options,
+			defaulted: n.defaulter}
+		if len(names) > 0 {
+			f.name = names[0]
+		}
+		if isNested(sf.Type()) {
+			var nested string
+			var p *types.Pointer
+			// untagged pointers are not followed, see env.BindWithOptions
+			if p, f.pointer = sf.Type().(*types.Pointer); f.pointer && raw == "" && !sf.Embedded() {
+				continue
+			}
+			if f.pointer && g.rolling[p.Elem()] {
+				return g.errorf(sf, "recursive structure %s is not supported, use env.Bind",
+					types.TypeString(p.Elem(), g.qualifier))
+			}
+			if nested, err = g.nestedPrefix(f, prefix); err != nil {
+				return g.errorf(sf, "%w", err)
+			}
+			f.nested = true
+			if err = g.roll(f, nested); err != nil {
+				return
+			}
+			if f.empty() {
+				continue
+			}
+			if err = g.accessible(sf); err != nil {
+				return
+			}
+			n.fields = append(n.fields, f)
+			continue
+		}
+		if raw == "" {
+			continue
+		}
+		if err = g.leaf(sf, f, names, prefix); err != nil {
+			return
+		}
+		n.fields = append(n.fields, f)
+	}
+	return
+}
+
+// leaf checks type and tag of the field bound from env variable and sets the name of env variable
+func (g *generator) leaf(sf *types.Var, f *node, names []string, prefix string) (err error) {
+	if err = g.accessible(sf); err != nil {
+		return
+	}
+	if s, ok := sf.Type().Underlying().(*types.Slice); ok && isNested(s.Elem()) {
+		return g.errorf(sf, "slices of structures are not supported, use env.Bind")
+	}
+	if !supported(sf.Type()) {
+		return g.errorf(sf, "unsupported type %s, use env.Bind", types.TypeString(sf.Type(), g.qualifier))
+	}
+	if len(names) > 1 {
+		return g.errorf(sf, "aliases are not supported, use env.Bind")
+	}
+	for _, key := range sortedKeys(f.options) {
+		if !supportedOptions[key] {
+			return g.errorf(sf, "option %s is not supported, use env.Bind", key)
+		}
+	}
+	if isTrue(f.options["noprefix"]) {
+		prefix = g.unprefixed(prefix)
+	}
+	f.name = g.join(prefix, f.name)
+	return
+}
+
+// nestedPrefix returns prefix of nested structure; see env.BindWithOptions
+func (g *generator) nestedPrefix(f *node, prefix string) (nested string, err error) {
+	root := g.prefix
+	if isTrue(f.options["noprefix"]) {
+		prefix = g.unprefixed(prefix)
+		root = ""
+	}
+	switch f.options["prefix"] {
+	case "", "inherit":
+		return g.join(prefix, f.name), nil
+	case "replace":
+		return g.join(root, f.name), nil
+	case "ignore":
+		return root, nil
+	}
+	return "", fmt.Errorf("invalid prefix=%s of %s, expected inherit, replace or ignore", f.options["prefix"],
+		g.join(prefix, f.name))
+}
+
+// accessible returns error if the field can't be accessed by generated code; i.e. unexported field of other package
+func (g *generator) accessible(sf *types.Var) error {
+	if !sf.Exported() && sf.Pkg() != g.pkg {
+		return g.errorf(sf, "unexported field of package %s can't be bound, use env.Bind", sf.Pkg().Path())
+	}
+	return nil
+}
+
+// binder writes function binding the root structure of type name
+func (g *generator) binder(w *bytes.Buffer, name string, root *node) {
+	fn := "Bind" + name
+	if !ast.IsExported(name) {
+		fn = "bind" + string(unicode.ToUpper(rune(name[0]))) + name[1:]
+	}
+	fmt.Fprintf(w, "\n// %s binds variables provided by l into c. It is equivalent to %s without reflection\n", fn,
+		g.equivalent())
+	fmt.Fprintf(w, "func %s(c *%s, l env.Lookuper) error {\n", fn, name)
+	fmt.Fprintf(w, "b, err := env.NewStaticBinder(l)\nif err != nil {\nreturn err\n}\n")
+	fmt.Fprintf(w, "if c == nil {\nreturn fmt.Errorf(\"argument must be pointer to structure\")\n}\n")
+	g.structure(w, root)
+	fmt.Fprintf(w, "return b.Done()\n}\n")
+}
+
+// structure writes binding of fields of nested structure n
+func (g *generator) structure(w *bytes.Buffer, n *node) {
+	if n.defaulter {
+		fmt.Fprintf(w, "%s.SetDefaults()\n", n.expr)
+	}
+	for _, f := range n.fields {
+		switch {
+		case f.nested && f.pointer:
+			g.pointer(w, f)
+		case f.nested:
+			g.structure(w, f)
+		default:
+			g.field(w, f)
+		}
+	}
+	if n.validator {
+		expr := n.expr
+		if !n.pointer && n.expr != "c" {
+			expr = "&" + expr
+		}
+		fmt.Fprintf(w, "b.Validate(%q, %s)\n", n.path, expr)
+	}
+}
+
+// pointer writes binding of structure referenced by pointer. Nil pointer is allocated only if some of env variables
+// of the structure exist, the same as env.Bind does
+func (g *generator) pointer(w *bytes.Buffer, n *node) {
+	names := n.leaves()
+	elem := types.TypeString(n.typ.(*types.Pointer).Elem(), g.qualifier)
+	if len(names) > 0 {
+		quoted := make([]string, 0, len(names))
+		for _, name := range names {
+			quoted = append(quoted, strconv.Quote(name))
+		}
+		fmt.Fprintf(w, "if %s == nil && b.Exists(%s) {\n%s = &%s{}\n}\n", n.expr, strings.Join(quoted, ", "),
+			n.expr, elem)
+	}
+	fmt.Fprintf(w, "if %s != nil {\n", n.expr)
+	g.structure(w, n)
+	fmt.Fprintf(w, "}\n")
+}
+
+// field writes binding of the field from env variable
+func (g *generator) field(w *bytes.Buffer, f *node) {
+	spec := []string{fmt.Sprintf("Field: %q", f.path), fmt.Sprintf("Name: %q", f.name)}
+	if def, found := f.options["default"]; found {
+		spec = append(spec, fmt.Sprintf("Default: %q", def), "HasDefault: true")
+	}
+	if f.options["require"] == "true" {
+		spec = append(spec, "Require: true")
+	}
+	if isTrue(f.options["protected"]) {
+		spec = append(spec, "Protected: "+protected(f))
+	}
+	if f.defaulted {
+		spec = append(spec, "Defaulted: true")
+	}
+	fmt.Fprintf(w, "b.Bind(env.Spec{%s}, &%s)\n", strings.Join(spec, ", "), f.expr)
+}
+
+// equivalent returns call of env package, which binds variables the same way as generated binder
+func (g *generator) equivalent() string {
+	if g.prefix == "" && g.separator == "_" {
+		return "env.BindFrom(l, c)"
+	}
+	opts := []string{"c", "env.WithLookuper(l)"}
+	if g.prefix != "" {
+		opts = append(opts, fmt.Sprintf("env.WithPrefix(%q)", g.prefix))
+	}
+	if g.separator != "_" {
+		opts = append(opts, fmt.Sprintf("env.WithSeparator(%q)", g.separator))
+	}
+	return fmt.Sprintf("env.BindWithOptions(%s)", strings.Join(opts, ", "))
+}
+
+// qualifier records imports of types used by generated code
+func (g *generator) qualifier(p *types.Package) string {
+	if p == g.pkg {
+		return ""
+	}
+	g.imports[p.Path()] = p.Name()
+	return p.Name()
+}
+
+// errorf returns error prefixed by position of the field
+func (g *generator) errorf(sf *types.Var, format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %s: %w", g.fset.Position(sf.Pos()), sf.Name(), fmt.Errorf(format, args...))
+}
+
+// join joins prefix and name by separator; see env.WithSeparator
+func (g *generator) join(prefix, name string) string {
+	switch {
+	case prefix == "":
+		return name
+	case name == "":
+		return prefix
+	}
+	return prefix + g.separator + name
+}
+
+// unprefixed removes the global prefix from the beginning of prefix of nested structure
+func (g *generator) unprefixed(prefix string) string {
+	if g.prefix == "" || prefix == g.prefix {
+		return strings.TrimPrefix(prefix, g.prefix)
+	}
+	return strings.TrimPrefix(prefix, g.prefix+g.separator)
+}
+
+// leaves returns names of env variables of nested structure
+func (n *node) leaves() (names []string) {
+	for _, f := range n.fields {
+		if f.nested {
+			names = append(names, f.leaves()...)
+			continue
+		}
+		names = append(names, f.name)
+	}
+	return
+}
+
+// empty returns true if generated binder has nothing to do with nested structure n
+func (n *node) empty() bool {
+	return len(n.fields) == 0 && !n.defaulter && !n.validator
+}
+
+// protected returns expression which is true if protected field f already has a value. Protected bool fields
+// are never overwritten, see env.Bind
+func protected(f *node) string {
+	t := f.typ.Underlying()
+	if b, ok := t.(*types.Basic); ok {
+		switch {
+		case b.Info()&types.IsBoolean != 0:
+			return "true"
+		case b.Info()&types.IsString != 0:
+			return f.expr + ` != ""`
+		}
+		return f.expr + " != 0"
+	}
+	return f.expr + " != nil"
+}
+
+// supported returns true if t is built-in type of env.Bind; i.e. string, bool, integers, floats, time.Duration,
+// slices of them or pointers to them
+func supported(t types.Type) bool {
+	switch u := t.(type) {
+	case *types.Slice:
+		return scalar(u.Elem())
+	case *types.Pointer:
+		return scalar(u.Elem())
+	}
+	return scalar(t)
+}
+
+// scalar returns true if t is string, bool, integer, float or time.Duration
+func scalar(t types.Type) bool {
+	if n, ok := t.(*types.Named); ok {
+		obj := n.Obj()
+		return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration"
+	}
+	b, ok := t.(*types.Basic)
+	if !ok {
+		return false
+	}
+	switch b.Kind() {
+	case types.String, types.Bool, types.Int, types.Int8, types.Int16, types.Int32, types.Int64, types.Uint,
+		types.Uint8, types.Uint16, types.Uint32, types.Uint64, types.Float32, types.Float64:
+		return true
+	}
+	return false
+}
+
+// isNested returns true if t is structure or pointer to structure which is not bound as a single value
+func isNested(t types.Type) bool {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	if _, ok := t.Underlying().(*types.Struct); !ok {
+		return false
+	}
+	// types implementing encoding.TextUnmarshaler are bound as a single value
+	return types.NewMethodSet(types.NewPointer(t)).Lookup(nil, "UnmarshalText") == nil
+}
+
+// hasMethod returns true if pointer to t has method name without parameters; returning error or nothing
+func hasMethod(t types.Type, name string, returnsError bool) bool {
+	sel := types.NewMethodSet(types.NewPointer(t)).Lookup(nil, name)
+	if sel == nil {
+		return false
+	}
+	sig := sel.Type().(*types.Signature)
+	if sig.Params().Len() != 0 {
+		return false
+	}
+	if !returnsError {
+		return sig.Results().Len() == 0
+	}
+	return sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), errorType)
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// isTrue returns true if s is boolean true; e.g. protected=true
+func isTrue(s string) bool {
+	b, _ := strconv.ParseBool(s)
+	return b
+}
+
+// sortedKeys returns keys of m in ascending order
+func sortedKeys(m map[string]string) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
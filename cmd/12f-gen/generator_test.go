@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGolden checks that generated binders in internal/golden are up to date; run go generate ./... if it fails.
+// Tests of internal/golden compare the binders with env.Bind
+func TestGolden(t *testing.T) {
+	t.Parallel()
+	const dir = "internal/golden"
+	tests := []config{
+		{types: []string{"Config"}, separator: "_", output: filepath.Join(dir, "config_env.go")},
+		{types: []string{"Sidecar"}, prefix: "ORDERS", separator: "__", output: filepath.Join(dir, "sidecar_env.go")},
+	}
+	for _, test := range tests {
+		expected, err := os.ReadFile(test.output)
+		assert.NoError(t, err)
+		src, err := generate(dir, test)
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), string(src), test.output)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		typ      string
+		src      string
+		expected string
+	}{
+		{"unknown type", "Unknown", "type Config struct{}", "type Unknown is not declared in package config"},
+		{"not structure", "Config", "type Config string", "type Config is not structure"},
+		{"tag syntax", "Config", "type Config struct { Port int `env:\"PORT, requried=true\"` }",
+			"config.go:3:22: Port: invalid tag of Config.Port at column 7: unknown option requried"},
+		{"unsupported option", "Config", "type Config struct { Port int `env:\"PORT, min=1\"` }",
+			"config.go:3:22: Port: option min is not supported, use env.Bind"},
+		{"aliases", "Config", "type Config struct { Level string `env:\"APP_LEVEL|LEVEL\"` }",
+			"config.go:3:22: Level: aliases are not supported, use env.Bind"},
+		{"map", "Config", "type Config struct { Labels map[string]string `env:\"LABELS\"` }",
+			"config.go:3:22: Labels: unsupported type map[string]string, use env.Bind"},
+		{"named type", "Config", "type Level string\ntype Config struct { Level Level `env:\"LEVEL\"` }",
+			"config.go:4:22: Level: unsupported type Level, use env.Bind"},
+		{"text unmarshaler", "Config", "import \"time\"\n\ntype Config struct { Start time.Time `env:\"START\"` }",
+			"config.go:5:22: Start: unsupported type time.Time, use env.Bind"},
+		{"slice of structures", "Config", "type E struct { URL string `env:\"URL\"` }\n" +
+			"type Config struct { Upstreams []E `env:\"UPSTREAM\"` }",
+			"config.go:4:22: Upstreams: slices of structures are not supported, use env.Bind"},
+		{"invalid prefix", "Config", "type E struct { URL string `env:\"URL\"` }\n" +
+			"type Config struct { Primary E `env:\"PRIMARY, prefix=keep\"` }",
+			"config.go:4:22: Primary: invalid prefix=keep of PRIMARY, expected inherit, replace or ignore"},
+		{"recursive structure", "Node", "type Node struct {\n\tName string `env:\"NAME\"`\n\tNext *Node `env:\"NEXT\"`\n}",
+			"config.go:5:2: Next: recursive structure Node is not supported, use env.Bind"},
+		{"indirect recursion", "A", "type A struct { B *B `env:\"B\"` }\ntype B struct { A *A `env:\"A\"` }",
+			"config.go:4:17: A: recursive structure A is not supported, use env.Bind"},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		src := "package config\n\n" + test.src + "\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.go"), []byte(src), 0600))
+		_, err := generate(dir, config{types: []string{test.typ}})
+		if assert.Error(t, err, test.name) {
+			assert.Equal(t, test.expected, filepath.Base(err.Error()), test.name)
+		}
+	}
+
+	// untagged pointers are not followed, so they don't make the structure recursive
+	dir := t.TempDir()
+	src := "package config\n\ntype Node struct {\n\tName string `env:\"NAME\"`\n\tNext *Node\n}\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.go"), []byte(src), 0600))
+	_, err := generate(dir, config{types: []string{"Node"}})
+	assert.NoError(t, err)
+}
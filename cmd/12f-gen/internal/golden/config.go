@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+// Package golden contains structures bound by binders generated by 12f-gen. Tests compare generated binders with
+// env.Bind, so the generated code must be regenerated whenever the generator changes
+package golden
+
+import (
+	"fmt"
+	"time"
+)
+
+//go:generate go run ../.. -type Config
+//go:generate go run ../.. -type Sidecar -prefix ORDERS -separator __ -output sidecar_env.go
+
+// Base is embedded into Config, its fields are not prefixed
+type Base struct {
+	Name    string `env:"NAME, require=true"`
+	Version string `env:"VERSION, default=v1"`
+}
+
+// Endpoint is nested structure
+type Endpoint struct {
+	URL     string        `env:"URL, require=true"`
+	Timeout time.Duration `env:"TIMEOUT, default=5s"`
+	Retries *int          `env:"RETRIES"`
+}
+
+// proxy has no defaults, so pointer to it stays nil unless some of its env variables exist
+type proxy struct {
+	URL  string `env:"URL, require=true"`
+	Port *int   `env:"PORT"`
+}
+
+// TLS sets its defaults and validates itself
+type TLS struct {
+	Enabled bool   `env:"ENABLED"`
+	Cert    string `env:"CERT"`
+	Key     string `env:"KEY"`
+	MinVer  uint16 `env:"MIN_VERSION"`
+}
+
+// SetDefaults sets defaults which are kept if env variables don't exist
+func (t *TLS) SetDefaults() {
+	t.MinVer = 12
+}
+
+// Validate checks that certificate and key are set together
+func (t *TLS) Validate() error {
+	if t.Enabled && (t.Cert == "" || t.Key == "") {
+		return fmt.Errorf("certificate and key are required")
+	}
+	return nil
+}
+
+// Config contains all kinds of fields supported by 12f-gen
+type Config struct {
+	Base
+	Port      int             `env:"PORT, default=8080"`
+	Debug     bool            `env:"DEBUG"`
+	Ratio     float64         `env:"RATIO, default=0.5"`
+	Small     int8            `env:"SMALL"`
+	Big       uint64          `env:"BIG"`
+	Weight    float32         `env:"WEIGHT"`
+	Interval  time.Duration   `env:"INTERVAL, default=1m"`
+	Hosts     []string        `env:"HOSTS, default=[localhost, 127.0.0.1]"`
+	Ports     []int           `env:"PORTS"`
+	Timeouts  []time.Duration `env:"TIMEOUTS, default=[1s,2s]"`
+	Flags     []bool          `env:"FLAGS"`
+	Limit     *int            `env:"LIMIT"`
+	Label     *string         `env:"LABEL, default=none"`
+	Token     string          `env:"TOKEN, protected=true"`
+	Locked    bool            `env:"LOCKED, protected=true"`
+	Zones     []string        `env:"ZONES, protected=true"`
+	Home      string          `env:"HOME, noprefix=true"`
+	Ignored   string          `env:"-"`
+	Untagged  string
+	Primary   Endpoint  `env:"PRIMARY"`
+	Failover  *Endpoint `env:"FAILOVER"`
+	Proxy     *proxy    `env:"PROXY"`
+	TLS       TLS       `env:"TLS"`
+	Logging   logging
+	Telemetry *telemetry `env:"TELEMETRY"`
+	secret    string     `env:"SECRET"`
+}
+
+// logging is untagged nested structure, its fields use prefix of the parent
+type logging struct {
+	Level  string `env:"LOG_LEVEL, default=info"`
+	Format string `env:"LOG_FORMAT"`
+}
+
+// telemetry is referenced by pointer, so it stays nil unless some of its env variables exist, although all of them
+// have default
+type telemetry struct {
+	Endpoint string `env:"ENDPOINT, default=localhost:4317"`
+	Ratio    uint8  `env:"RATIO, default=10"`
+}
+
+// Validate checks that port is not privileged
+func (c *Config) Validate() error {
+	if c.Port < 1024 {
+		return fmt.Errorf("port %d is privileged", c.Port)
+	}
+	return nil
+}
+
+// Sidecar is bound with global prefix and separator
+type Sidecar struct {
+	Port     int      `env:"PORT, default=9000"`
+	LogLevel string   `env:"LOG_LEVEL, noprefix=true"`
+	Upstream Endpoint `env:"UPSTREAM"`
+	Metrics  Endpoint `env:"METRICS, prefix=ignore"`
+	Admin    *proxy   `env:"ADMIN, noprefix=true"`
+	Backup   *proxy   `env:"BACKUP, prefix=replace"`
+}
@@ -0,0 +1,75 @@
+// Code generated by 12f-gen; DO NOT EDIT.
+
+package golden
+
+import (
+	"fmt"
+
+	"github.com/kuritka/12f/env"
+)
+
+// BindConfig binds variables provided by l into c. It is equivalent to env.BindFrom(l, c) without reflection
+func BindConfig(c *Config, l env.Lookuper) error {
+	b, err := env.NewStaticBinder(l)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return fmt.Errorf("argument must be pointer to structure")
+	}
+	b.Bind(env.Spec{Field: "Config.Base.Name", Name: "NAME", Require: true}, &c.Base.Name)
+	b.Bind(env.Spec{Field: "Config.Base.Version", Name: "VERSION", Default: "v1", HasDefault: true}, &c.Base.Version)
+	b.Bind(env.Spec{Field: "Config.Port", Name: "PORT", Default: "8080", HasDefault: true}, &c.Port)
+	b.Bind(env.Spec{Field: "Config.Debug", Name: "DEBUG"}, &c.Debug)
+	b.Bind(env.Spec{Field: "Config.Ratio", Name: "RATIO", Default: "0.5", HasDefault: true}, &c.Ratio)
+	b.Bind(env.Spec{Field: "Config.Small", Name: "SMALL"}, &c.Small)
+	b.Bind(env.Spec{Field: "Config.Big", Name: "BIG"}, &c.Big)
+	b.Bind(env.Spec{Field: "Config.Weight", Name: "WEIGHT"}, &c.Weight)
+	b.Bind(env.Spec{Field: "Config.Interval", Name: "INTERVAL", Default: "1m", HasDefault: true}, &c.Interval)
+	b.Bind(env.Spec{Field: "Config.Hosts", Name: "HOSTS", Default: "[localhost, 127.0.0.1]", HasDefault: true}, &c.Hosts)
+	b.Bind(env.Spec{Field: "Config.Ports", Name: "PORTS"}, &c.Ports)
+	b.Bind(env.Spec{Field: "Config.Timeouts", Name: "TIMEOUTS", Default: "[1s,2s]", HasDefault: true}, &c.Timeouts)
+	b.Bind(env.Spec{Field: "Config.Flags", Name: "FLAGS"}, &c.Flags)
+	b.Bind(env.Spec{Field: "Config.Limit", Name: "LIMIT"}, &c.Limit)
+	b.Bind(env.Spec{Field: "Config.Label", Name: "LABEL", Default: "none", HasDefault: true}, &c.Label)
+	b.Bind(env.Spec{Field: "Config.Token", Name: "TOKEN", Protected: c.Token != ""}, &c.Token)
+	b.Bind(env.Spec{Field: "Config.Locked", Name: "LOCKED", Protected: true}, &c.Locked)
+	b.Bind(env.Spec{Field: "Config.Zones", Name: "ZONES", Protected: c.Zones != nil}, &c.Zones)
+	b.Bind(env.Spec{Field: "Config.Home", Name: "HOME"}, &c.Home)
+	b.Bind(env.Spec{Field: "Config.Primary.URL", Name: "PRIMARY_URL", Require: true}, &c.Primary.URL)
+	b.Bind(env.Spec{Field: "Config.Primary.Timeout", Name: "PRIMARY_TIMEOUT", Default: "5s", HasDefault: true}, &c.Primary.Timeout)
+	b.Bind(env.Spec{Field: "Config.Primary.Retries", Name: "PRIMARY_RETRIES"}, &c.Primary.Retries)
+	if c.Failover == nil && b.Exists("FAILOVER_URL", "FAILOVER_TIMEOUT", "FAILOVER_RETRIES") {
+		c.Failover = &Endpoint{}
+	}
+	if c.Failover != nil {
+		b.Bind(env.Spec{Field: "Config.Failover.URL", Name: "FAILOVER_URL", Require: true}, &c.Failover.URL)
+		b.Bind(env.Spec{Field: "Config.Failover.Timeout", Name: "FAILOVER_TIMEOUT", Default: "5s", HasDefault: true}, &c.Failover.Timeout)
+		b.Bind(env.Spec{Field: "Config.Failover.Retries", Name: "FAILOVER_RETRIES"}, &c.Failover.Retries)
+	}
+	if c.Proxy == nil && b.Exists("PROXY_URL", "PROXY_PORT") {
+		c.Proxy = &proxy{}
+	}
+	if c.Proxy != nil {
+		b.Bind(env.Spec{Field: "Config.Proxy.URL", Name: "PROXY_URL", Require: true}, &c.Proxy.URL)
+		b.Bind(env.Spec{Field: "Config.Proxy.Port", Name: "PROXY_PORT"}, &c.Proxy.Port)
+	}
+	c.TLS.SetDefaults()
+	b.Bind(env.Spec{Field: "Config.TLS.Enabled", Name: "TLS_ENABLED", Defaulted: true}, &c.TLS.Enabled)
+	b.Bind(env.Spec{Field: "Config.TLS.Cert", Name: "TLS_CERT", Defaulted: true}, &c.TLS.Cert)
+	b.Bind(env.Spec{Field: "Config.TLS.Key", Name: "TLS_KEY", Defaulted: true}, &c.TLS.Key)
+	b.Bind(env.Spec{Field: "Config.TLS.MinVer", Name: "TLS_MIN_VERSION", Defaulted: true}, &c.TLS.MinVer)
+	b.Validate("Config.TLS", &c.TLS)
+	b.Bind(env.Spec{Field: "Config.Logging.Level", Name: "LOG_LEVEL", Default: "info", HasDefault: true}, &c.Logging.Level)
+	b.Bind(env.Spec{Field: "Config.Logging.Format", Name: "LOG_FORMAT"}, &c.Logging.Format)
+	if c.Telemetry == nil && b.Exists("TELEMETRY_ENDPOINT", "TELEMETRY_RATIO") {
+		c.Telemetry = &telemetry{}
+	}
+	if c.Telemetry != nil {
+		b.Bind(env.Spec{Field: "Config.Telemetry.Endpoint", Name: "TELEMETRY_ENDPOINT", Default: "localhost:4317", HasDefault: true}, &c.Telemetry.Endpoint)
+		b.Bind(env.Spec{Field: "Config.Telemetry.Ratio", Name: "TELEMETRY_RATIO", Default: "10", HasDefault: true}, &c.Telemetry.Ratio)
+	}
+	b.Bind(env.Spec{Field: "Config.secret", Name: "SECRET"}, &c.secret)
+	b.Validate("Config", c)
+	return b.Done()
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package golden
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kuritka/12f/env"
+	"github.com/stretchr/testify/assert"
+)
+
+// required contains variables which must be set to bind Config successfully
+var required = env.MapLookuper{
+	"NAME":        "orders",
+	"PRIMARY_URL": "https://primary",
+	"PORT":        "8080",
+}
+
+// with returns required variables merged with vars
+func with(vars env.MapLookuper) env.MapLookuper {
+	m := env.MapLookuper{}
+	for _, l := range []env.MapLookuper{required, vars} {
+		for k, v := range l {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// errorTypes returns types of errors aggregated by BindError
+func errorTypes(err error) (types []string) {
+	if be, ok := err.(*env.BindError); ok {
+		for _, e := range be.Errors {
+			types = append(types, fmt.Sprintf("%T", e))
+		}
+	}
+	return
+}
+
+// assertSame checks that generated binder returns the same error as env.Bind
+func assertSame(t *testing.T, name string, expected, actual error) {
+	if expected == nil {
+		assert.NoError(t, actual, name)
+		return
+	}
+	assert.EqualError(t, actual, expected.Error(), name)
+	assert.Equal(t, errorTypes(expected), errorTypes(actual), name)
+}
+
+func TestBindConfig(t *testing.T) {
+	t.Parallel()
+	limit, label := 5, "preset"
+	tests := []struct {
+		name string
+		vars env.MapLookuper
+		init func() *Config
+	}{
+		{name: "defaults", vars: required},
+		{name: "missing required", vars: env.MapLookuper{}},
+		{name: "all variables", vars: with(env.MapLookuper{
+			"VERSION": "v2", "DEBUG": "true", "RATIO": "0.25", "SMALL": "-128", "BIG": "18446744073709551615",
+			"WEIGHT": "1.5", "INTERVAL": "90s", "HOSTS": "a, b ,c", "PORTS": "80, 443", "TIMEOUTS": "1ms,2h",
+			"FLAGS": "true,false", "LIMIT": "10", "LABEL": "prod", "TOKEN": "secret", "LOCKED": "true",
+			"ZONES": "eu,us", "HOME": "/home", "Untagged": "x", "Ignored": "x", "PRIMARY_TIMEOUT": "1s",
+			"PRIMARY_RETRIES": "3", "FAILOVER_TIMEOUT": "2s", "FAILOVER_RETRIES": "4", "PROXY_URL": "http://proxy",
+			"PROXY_PORT": "3128", "TLS_ENABLED": "true", "TLS_CERT": "cert", "TLS_KEY": "key",
+			"TLS_MIN_VERSION": "13", "FAILOVER_URL": "https://failover", "LOG_LEVEL": "debug", "LOG_FORMAT": "json",
+			"TELEMETRY_ENDPOINT": "otel:4317", "TELEMETRY_RATIO": "50", "SECRET": "hidden"})},
+		{name: "empty values", vars: with(env.MapLookuper{
+			"VERSION": "", "HOSTS": "", "PORTS": "", "TIMEOUTS": "", "LABEL": "", "LOG_LEVEL": "", "HOME": ""})},
+		{name: "invalid values", vars: with(env.MapLookuper{
+			"PORT": "http", "DEBUG": "yes", "SMALL": "128", "BIG": "-1", "WEIGHT": "1e40", "INTERVAL": "1",
+			"PORTS": "80,x", "TIMEOUTS": "1s,2", "FLAGS": "1,2", "LIMIT": "1.5", "PRIMARY_RETRIES": "x",
+			"TLS_MIN_VERSION": "70000", "TELEMETRY_RATIO": "256"})},
+		{name: "protected fields", vars: with(env.MapLookuper{"TOKEN": "new", "LOCKED": "true", "ZONES": "us"}),
+			init: func() *Config {
+				return &Config{Token: "old", Zones: []string{"eu"}}
+			}},
+		{name: "protected zero values", vars: with(env.MapLookuper{"TOKEN": "new", "LOCKED": "true", "ZONES": "us"}),
+			init: func() *Config {
+				return &Config{Zones: []string{}}
+			}},
+		{name: "overwritten values", vars: required, init: func() *Config {
+			return &Config{Debug: true, Ports: []int{1}, Limit: &limit, Label: &label, Untagged: "kept",
+				Ignored: "kept", Logging: logging{Format: "text"}}
+		}},
+		{name: "nil pointer", vars: with(env.MapLookuper{"PROXY_PORT": "3128"})},
+		{name: "existing pointers", vars: required, init: func() *Config {
+			return &Config{Proxy: &proxy{URL: "http://proxy"}, Failover: &Endpoint{Timeout: 1}}
+		}},
+		{name: "missing pointers", vars: env.MapLookuper{"NAME": "orders", "PRIMARY_URL": "https://primary"}},
+		{name: "optional pointers", vars: with(env.MapLookuper{"FAILOVER_TIMEOUT": "2s", "TELEMETRY_RATIO": "50"})},
+		{name: "structure validation", vars: with(env.MapLookuper{"TLS_ENABLED": "true"})},
+		{name: "root validation", vars: with(env.MapLookuper{"PORT": "80"})},
+		{name: "nested and root validation", vars: with(env.MapLookuper{"PORT": "80", "TLS_ENABLED": "true"})},
+		{name: "validation after errors", vars: with(env.MapLookuper{"PORT": "80", "SMALL": "x"})},
+	}
+	for _, test := range tests {
+		expected, actual := &Config{}, &Config{}
+		if test.init != nil {
+			expected, actual = test.init(), test.init()
+		}
+		expectedErr := env.BindFrom(test.vars, expected)
+		actualErr := BindConfig(actual, test.vars)
+		assertSame(t, test.name, expectedErr, actualErr)
+		assert.Equal(t, expected, actual, test.name)
+	}
+
+	// pointers stay nil if none of their variables exist, although their fields have defaults
+	c := &Config{}
+	assert.NoError(t, BindConfig(c, required))
+	assert.Nil(t, c.Failover)
+	assert.Nil(t, c.Telemetry)
+}
+
+func TestBindSidecar(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		vars env.MapLookuper
+	}{
+		{name: "missing required", vars: env.MapLookuper{}},
+		{name: "prefixed", vars: env.MapLookuper{
+			"ORDERS__PORT": "9001", "LOG_LEVEL": "debug", "ORDERS__LOG_LEVEL": "info", "ORDERS__UPSTREAM__URL": "up",
+			"ORDERS__URL": "metrics", "ORDERS__TIMEOUT": "1s", "ADMIN__URL": "admin", "ADMIN__PORT": "8081",
+			"ORDERS__BACKUP__PORT": "1"}},
+		{name: "unprefixed", vars: env.MapLookuper{
+			"PORT": "9001", "UPSTREAM__URL": "up", "URL": "metrics", "ORDERS__ADMIN__URL": "admin",
+			"BACKUP__URL": "backup", "ORDERS_PORT": "1"}},
+	}
+	for _, test := range tests {
+		expected, actual := &Sidecar{}, &Sidecar{}
+		expectedErr := env.BindWithOptions(expected, env.WithLookuper(test.vars), env.WithPrefix("ORDERS"),
+			env.WithSeparator("__"))
+		actualErr := BindSidecar(actual, test.vars)
+		assertSame(t, test.name, expectedErr, actualErr)
+		assert.Equal(t, expected, actual, test.name)
+	}
+}
+
+func TestInvalidArguments(t *testing.T) {
+	t.Parallel()
+	assertSame(t, "nil lookuper", env.BindFrom(nil, &Config{}), BindConfig(&Config{}, nil))
+	assertSame(t, "nil structure", env.BindFrom(required, (*Config)(nil)), BindConfig(nil, required))
+}
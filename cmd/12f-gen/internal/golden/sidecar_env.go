@@ -0,0 +1,43 @@
+// Code generated by 12f-gen; DO NOT EDIT.
+
+package golden
+
+import (
+	"fmt"
+
+	"github.com/kuritka/12f/env"
+)
+
+// BindSidecar binds variables provided by l into c. It is equivalent to env.BindWithOptions(c, env.WithLookuper(l), env.WithPrefix("ORDERS"), env.WithSeparator("__")) without reflection
+func BindSidecar(c *Sidecar, l env.Lookuper) error {
+	b, err := env.NewStaticBinder(l)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return fmt.Errorf("argument must be pointer to structure")
+	}
+	b.Bind(env.Spec{Field: "Sidecar.Port", Name: "ORDERS__PORT", Default: "9000", HasDefault: true}, &c.Port)
+	b.Bind(env.Spec{Field: "Sidecar.LogLevel", Name: "LOG_LEVEL"}, &c.LogLevel)
+	b.Bind(env.Spec{Field: "Sidecar.Upstream.URL", Name: "ORDERS__UPSTREAM__URL", Require: true}, &c.Upstream.URL)
+	b.Bind(env.Spec{Field: "Sidecar.Upstream.Timeout", Name: "ORDERS__UPSTREAM__TIMEOUT", Default: "5s", HasDefault: true}, &c.Upstream.Timeout)
+	b.Bind(env.Spec{Field: "Sidecar.Upstream.Retries", Name: "ORDERS__UPSTREAM__RETRIES"}, &c.Upstream.Retries)
+	b.Bind(env.Spec{Field: "Sidecar.Metrics.URL", Name: "ORDERS__URL", Require: true}, &c.Metrics.URL)
+	b.Bind(env.Spec{Field: "Sidecar.Metrics.Timeout", Name: "ORDERS__TIMEOUT", Default: "5s", HasDefault: true}, &c.Metrics.Timeout)
+	b.Bind(env.Spec{Field: "Sidecar.Metrics.Retries", Name: "ORDERS__RETRIES"}, &c.Metrics.Retries)
+	if c.Admin == nil && b.Exists("ADMIN__URL", "ADMIN__PORT") {
+		c.Admin = &proxy{}
+	}
+	if c.Admin != nil {
+		b.Bind(env.Spec{Field: "Sidecar.Admin.URL", Name: "ADMIN__URL", Require: true}, &c.Admin.URL)
+		b.Bind(env.Spec{Field: "Sidecar.Admin.Port", Name: "ADMIN__PORT"}, &c.Admin.Port)
+	}
+	if c.Backup == nil && b.Exists("ORDERS__BACKUP__URL", "ORDERS__BACKUP__PORT") {
+		c.Backup = &proxy{}
+	}
+	if c.Backup != nil {
+		b.Bind(env.Spec{Field: "Sidecar.Backup.URL", Name: "ORDERS__BACKUP__URL", Require: true}, &c.Backup.URL)
+		b.Bind(env.Spec{Field: "Sidecar.Backup.Port", Name: "ORDERS__BACKUP__PORT"}, &c.Backup.Port)
+	}
+	return b.Done()
+}
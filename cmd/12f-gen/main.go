@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+// 12f-gen generates binders of structures with env tags, which don't use reflection. It is intended to be run by
+// go generate; e.g.
+//
+//	//go:generate go run github.com/kuritka/12f/cmd/12f-gen -type Config
+//
+// creates config_env.go with function BindConfig(c *Config, l env.Lookuper) error, which binds variables the same way
+// as env.BindFrom(l, c)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	var c config
+	typeNames := flag.String("type", "", "comma-separated list of type names; must be set")
+	flag.StringVar(&c.prefix, "prefix", "", "prefix of all env variables, the same as env.WithPrefix")
+	flag.StringVar(&c.separator, "separator", "_", "separator of prefixes and names, the same as env.WithSeparator")
+	output := flag.String("output", "", "output file name; default <dir>/<type>_env.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: 12f-gen -type T [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("12f-gen: ")
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	c.types = strings.Split(*typeNames, ",")
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	c.output = *output
+	if c.output == "" {
+		c.output = filepath.Join(dir, strings.ToLower(c.types[0])+"_env.go")
+	}
+	src, err := generate(dir, c)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// #nosec G306; generated source code is not secret
+	if err = os.WriteFile(c.output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -18,12 +18,14 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package env
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"reflect"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 	"unsafe"
 )
 
@@ -33,32 +35,89 @@ type field struct {
 	fieldType  *reflect.Type
 	fieldValue *reflect.Value
 	public     bool
+	// path of the field within bound structure; e.g. Config.Credentials.KeyID
+	path string
+	// error which occurred while building meta
+	err error
+	// the field is kept untouched if env variable doesn't exist and has no default; set for fields of structures
+	// implementing Defaulter
+	keep bool
+	// the entry is a structure implementing Validator, which is validated after binding
+	validator bool
+}
+
+// tagError returns TagError pointing at the option of env tag of the field
+func (f field) tagError(option strTag, format string, args ...interface{}) error {
+	return &TagError{Field: f.path, Tag: f.env.tag, Column: option.column, Message: fmt.Sprintf(format, args...)}
 }
 
 // contains raw info about string tag field. e.g: default=hello,
 type strTag struct {
 	value  string
 	exists bool
+	// column of the option within env tag, starting at 1
+	column int
 }
 
 type env struct {
-	value     string
-	name      string
-	tagName   string
-	def       strTag
-	req       strTag
-	protected strTag
-	present   bool
+	// content of env tag; e.g. PORT, min=1
+	tag         string
+	value       string
+	name        string
+	field       string
+	tagName     string
+	def         strTag
+	req         strTag
+	protected   strTag
+	separator   strTag
+	kvSeparator strTag
+	min         strTag
+	max         strTag
+	oneof       strTag
+	pattern     strTag
+	// conditional requirements; e.g. required_if=TLS_ENABLED:true
+	requiredIf     strTag
+	requiredUnless strTag
+	// name of the group of mutually exclusive variables
+	exclusive strTag
+	// message of deprecated variable; e.g. use FOO_V2
+	deprecated strTag
+	// name or alias of env variable which supplied the value; empty if the variable doesn't exist
+	source  string
+	present bool
 }
 
-type meta map[string]field
+// meta contains fields in order of declaration. Nested structures are expanded in place of the field, so the
+// fields are bound top-down, depth-first
+type meta []field
 
 // Bind binds environment variables into structure
 func Bind(s interface{}) (err error) {
+	return BindWithOptions(s)
+}
+
+// BindFrom binds variables provided by lookuper into structure; e.g. BindFrom(MapLookuper{"PORT": "8080"}, &cfg)
+func BindFrom(l Lookuper, s interface{}) (err error) {
+	return BindWithOptions(s, WithLookuper(l))
+}
+
+// BindWithPrefix binds environment variables with prefix into structure; e.g. ORDERS_PORT is bound into the field
+// tagged env:"PORT" by BindWithPrefix(&cfg, "ORDERS")
+func BindWithPrefix(s interface{}, prefix string, opts ...Option) (err error) {
+	return BindWithOptions(s, append(opts, WithPrefix(prefix))...)
+}
+
+// BindWithOptions binds environment variables into structure and applies options; e.g.
+// WithDecoder(reflect.TypeOf(Color{}), parseColor)
+func BindWithOptions(s interface{}, opts ...Option) (err error) {
 	var meta meta
+	o := newOptions(opts...)
 	if s == nil {
 		return fmt.Errorf("invalid argument value (nil)")
 	}
+	if o.lookuper == nil {
+		return fmt.Errorf("invalid lookuper (nil)")
+	}
 	v := reflect.ValueOf(s)
 	t := reflect.TypeOf(s).Kind()
 	if t != reflect.Ptr {
@@ -67,283 +126,522 @@ func Bind(s interface{}) (err error) {
 	if v.Elem().Kind() != reflect.Struct {
 		return fmt.Errorf("argument must be pointer to structure")
 	}
-	meta, err = roll(v.Elem(), v.Elem().Type().Name(), "")
-	if err != nil {
-		return
-	}
-	err = bind(meta)
+	typ := v.Elem().Type()
+	meta = roll(make([]field, 0, metaSize(typ, o)), v.Elem(), typ.Name(), o.prefix, o)
+	sizes.Store(cacheKey{t: typ, autoNames: o.autoNames}, len(meta))
+	err = bind(meta, o)
 	return
 }
 
-// binds meta to structure pointer
-func bind(m meta) (err error) {
-	for k, v := range m {
-		f := reflect.NewAt(v.fieldValue.Type(), unsafe.Pointer(v.fieldValue.UnsafeAddr())).Elem()
-		switch f.Interface().(type) {
-		case bool:
-			var b bool
-			if v.env.protected.isTrue() {
-				continue
-			}
-			b, err = boolean(v.env)
-			if err != nil {
-				return
-			}
-			f.SetBool(b)
+// binds meta to structure pointer. Fields are processed in order of declaration and all errors are collected
+// into BindError. Structures implementing Validator are validated bottom-up if all fields were bound successfully
+func bind(m meta, o *options) (err error) {
+	var errs []error
+	c := newConditions(m, o)
+	for _, v := range m {
+		if v.validator {
 			continue
-
-		case int, int8, int16, int32, int64:
-			if v.env.protected.isTrue() && v.fieldValue.Int() != 0 {
-				continue
-			}
-			err = setNumeric(f, v)
-			if err != nil {
-				return
-			}
+		}
+		if v.err != nil {
+			errs = append(errs, v.err)
 			continue
-
-		case float32, float64:
-			if v.env.protected.isTrue() && v.fieldValue.Float() != 0 {
-				continue
-			}
-			err = setNumeric(f, v)
-			if err != nil {
-				return
-			}
+		}
+		if !v.env.present && v.env.req.value == "true" {
+			errs = append(errs, &MissingError{Field: v.path, Name: v.env.name})
 			continue
-
-		case uint, uint8, uint16, uint32, uint64:
-			if v.env.protected.isTrue() && v.fieldValue.Uint() != 0 {
-				continue
-			}
-			err = setNumeric(f, v)
-			if err != nil {
-				return
-			}
+		}
+		if err = c.required(v); err != nil {
+			errs = append(errs, err)
 			continue
-
-		case string:
-			var s string
-			if v.env.protected.isTrue() && v.fieldValue.String() != "" {
-				continue
-			}
-			s = GetEnvAsStringOrFallback(v.env.name, v.env.def.value)
-			f.SetString(s)
+		}
+		if err = c.exclusive(v); err != nil {
+			errs = append(errs, err)
 			continue
-
-		case []string:
-			if v.env.protected.isTrue() && !v.fieldValue.IsNil() {
-				continue
-			}
-			var ss []string
-			ss = GetEnvAsArrayOfStringsOrFallback(v.env.name, v.env.def.asStringSlice())
-			f.Set(reflect.ValueOf(ss))
+		}
+		if !v.env.present && !v.env.def.exists && v.keep {
 			continue
-
-		case []int, []int8, []int16, []int32, []int64, []float32, []float64, []uint, []uint8, []uint16, []uint32, []uint64:
-			if v.env.protected.isTrue() && !v.fieldValue.IsNil() {
+		}
+		if v.env.present {
+			if err = o.supplied(v); err != nil {
+				errs = append(errs, err)
 				continue
 			}
-			var floats []float64
-			floats, err = floatSlice(v.env)
-			if err != nil {
-				return
-			}
-			setNumericSlice(f, floats)
+		}
+		f := settable(*v.fieldValue)
+		if err = bindField(f, v, o); err != nil {
+			errs = append(errs, err)
 			continue
+		}
+		if err = validate(f, v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		errs = validateStructures(m)
+	}
+	if len(errs) != 0 {
+		return &BindError{Errors: errs}
+	}
+	return nil
+}
 
-		case []bool:
-			if v.env.protected.isTrue() && !v.fieldValue.IsNil() {
-				continue
-			}
-			var bs []bool
-			bs, err = boolSlice(v.env)
-			if err != nil {
+// bindField binds env variable into field value f
+func bindField(f reflect.Value, v field, o *options) (err error) {
+	if _, ok := o.parser(f.Type()); ok {
+		if v.env.protected.isTrue() && !f.IsZero() {
+			return
+		}
+		return setScalar(f, v, o)
+	}
+	if f.Kind() == reflect.Ptr {
+		return setPointer(f, v, o)
+	}
+	if f.Kind() == reflect.Slice {
+		if _, ok := o.parser(f.Type().Elem()); ok {
+			if v.env.protected.isTrue() && !f.IsNil() {
 				return
 			}
-			f.Set(reflect.ValueOf(bs))
-			continue
+			return setSlice(f, v, o)
+		}
+	}
+	if f.Kind() == reflect.Map && f.Type().Key().Kind() == reflect.String && isScalar(f.Type().Elem(), o) {
+		if v.env.protected.isTrue() && !f.IsNil() {
+			return
+		}
+		var mv reflect.Value
+		mv, err = mapping(v.env, f.Type(), o)
+		if err != nil {
+			return
+		}
+		f.Set(mv)
+		return
+	}
+	switch f.Interface().(type) {
+	case bool:
+		if v.env.protected.isTrue() {
+			return
+		}
+		return setScalar(f, v, o)
 
-		default:
-			err = fmt.Errorf("unsupported type %s: %s", k, v.fieldValue.Type().Name())
+	case time.Duration, int, int8, int16, int32, int64:
+		if v.env.protected.isTrue() && f.Int() != 0 {
+			return
 		}
+		return setScalar(f, v, o)
+
+	case float32, float64:
+		if v.env.protected.isTrue() && f.Float() != 0 {
+			return
+		}
+		return setScalar(f, v, o)
+
+	case uint, uint8, uint16, uint32, uint64:
+		if v.env.protected.isTrue() && f.Uint() != 0 {
+			return
+		}
+		return setScalar(f, v, o)
+
+	case string:
+		if v.env.protected.isTrue() && f.String() != "" {
+			return
+		}
+		return setScalar(f, v, o)
+
+	case []string:
+		if v.env.protected.isTrue() && !f.IsNil() {
+			return
+		}
+		f.Set(reflect.ValueOf(strSlice(v.env)))
+		return
+
+	case []int, []int8, []int16, []int32, []int64, []float32, []float64, []uint, []uint8, []uint16, []uint32, []uint64,
+		[]time.Duration, []bool:
+		if v.env.protected.isTrue() && !f.IsNil() {
+			return
+		}
+		return setSlice(f, v, o)
+
+	default:
+		err = &UnsupportedTypeError{Field: v.path, Name: v.env.name, Type: f.Type()}
 	}
 	return err
 }
 
-func setNumericSlice(f reflect.Value, floats []float64) {
-	switch f.Interface().(type) {
-	case []uint:
-		f.Set(reflect.ValueOf(convertToUInt(floats)))
-		return
-	case []uint8:
-		f.Set(reflect.ValueOf(convertToUInt8(floats)))
-		return
-	case []uint16:
-		f.Set(reflect.ValueOf(convertToUInt16(floats)))
-		return
-	case []uint32:
-		f.Set(reflect.ValueOf(convertToUInt32(floats)))
-		return
-	case []uint64:
-		f.Set(reflect.ValueOf(convertToUInt64(floats)))
-		return
-	case []int:
-		f.Set(reflect.ValueOf(convertToInt(floats)))
-		return
-	case []int8:
-		f.Set(reflect.ValueOf(convertToInt8(floats)))
-		return
-	case []int16:
-		f.Set(reflect.ValueOf(convertToInt16(floats)))
-		return
-	case []int32:
-		f.Set(reflect.ValueOf(convertToInt32(floats)))
-		return
-	case []int64:
-		f.Set(reflect.ValueOf(convertToInt64(floats)))
+// settable returns value which can be set even if the field is not exported
+func settable(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// setPointer allocates and binds pointer f. Pointer is set to nil if env variable doesn't exist and has no default,
+// so the caller can distinguish unset variable from the zero value
+func setPointer(f reflect.Value, v field, o *options) (err error) {
+	if v.env.protected.isTrue() && !f.IsNil() {
 		return
-	case []float32:
-		f.Set(reflect.ValueOf(convertToFloat32(floats)))
+	}
+	if !v.env.present && !v.env.def.exists {
+		f.Set(reflect.Zero(f.Type()))
 		return
-	case []float64:
-		f.Set(reflect.ValueOf(floats))
+	}
+	p := reflect.New(f.Type().Elem())
+	v.env.protected = strTag{}
+	if err = bindField(p.Elem(), v, o); err != nil {
 		return
 	}
+	f.Set(p)
+	return
 }
 
-func setNumeric(f reflect.Value, v field) (err error) {
-	var fl float64
-	fl, err = float(v.env)
+func setScalar(f reflect.Value, v field, o *options) (err error) {
+	var sv reflect.Value
+	sv, err = scalar(v.env, f.Type(), o)
 	if err != nil {
 		return
 	}
-	switch f.Interface().(type) {
-	case int, int8, int16, int32, int64:
-		f.SetInt(int64(fl))
-		return
-	case float32, float64:
-		f.SetFloat(fl)
-		return
-	case uint, uint8, uint16, uint32, uint64:
-		f.SetUint(uint64(fl))
+	f.Set(sv)
+	return
+}
+
+func setSlice(f reflect.Value, v field, o *options) (err error) {
+	var sv reflect.Value
+	sv, err = slice(v.env, f.Type(), o)
+	if err != nil {
 		return
 	}
+	f.Set(sv)
 	return
 }
 
-// recoursive function builds meta structure
-func roll(value reflect.Value, n, prefix string) (m meta, err error) {
-	const tagEnv = "env"
-
-	m = meta{}
-	for i := 0; i < value.NumField(); i++ {
+// recoursive function appends fields of the structure to meta. Errors of tags and nested structures are stored in
+// meta, so they can be reported together with binding errors. SetDefaults() of the structure is called before its
+// fields are rolled and the structure is appended behind its fields if it implements Validator
+func roll(m meta, value reflect.Value, n, prefix string, o *options) meta {
+	o.rolling[value.Type()]++
+	defer func() { o.rolling[value.Type()]-- }()
+	defaulter, keep := hook(value).(Defaulter)
+	if keep {
+		defaulter.SetDefaults()
+	}
+	for i, sf := range structFields(value.Type(), o) {
 		var e env
+		var err error
+		if sf.skip {
+			continue
+		}
 		vf := value.Field(i)
-		tf := value.Type().Field(i)
-		key := fmt.Sprintf("%s.%s", n, tf.Name)
-		tag := tf.Tag.Get(tagEnv)
-		if vf.Kind() == reflect.Struct {
-			var sm meta
-			prefix := strings.TrimPrefix(fmt.Sprintf("%s_%s", prefix, getTagName(tag)), "_")
-			sm, err = roll(vf, key, prefix)
-			if err != nil {
-				return
+		key := sf.name
+		if n != "" {
+			key = n + "." + sf.name
+		}
+		if sf.err != nil {
+			m = append(m, field{fieldName: sf.name, path: key, err: sf.tagError(key)})
+			continue
+		}
+		if isNested(sf.typ, o) {
+			var nested string
+			// untagged pointers are not followed unless AutoNames derives their names
+			if sf.typ.Kind() == reflect.Ptr && sf.raw == "" && !sf.anonymous {
+				continue
 			}
-			for k, v := range sm {
-				m[k] = v
+			if nested, err = nestedPrefix(sf, key, prefix, o); err != nil {
+				m = append(m, field{fieldName: sf.name, path: key, err: err})
+				continue
 			}
+			m = rollNested(m, vf, key, nested, o)
 			continue
 		}
-		if tag == "" {
+		if sf.raw == "" {
 			continue
 		}
-		if e, err = parseTag(tag, prefix); err != nil {
-			return
-		}
-		if !e.present && e.req.value == "true" {
-			err = fmt.Errorf("%s is required", e.name)
-			return
+		if sf.typ.Kind() == reflect.Slice && isNested(sf.typ.Elem(), o) {
+			m = rollSlice(m, vf, key, sf, prefix, o)
+			continue
 		}
-		m[key] = field{
+		e, err = parseTag(sf, key, prefix, o)
+		e.field = key
+		typ := sf.typ
+		m = append(m, field{
 			env:        e,
-			fieldName:  tf.Name,
-			fieldType:  &tf.Type,
+			fieldName:  sf.name,
+			fieldType:  &typ,
 			fieldValue: &vf,
-			public:     tf.PkgPath == "",
+			public:     sf.public,
+			path:       key,
+			err:        err,
+			keep:       keep,
+		})
+	}
+	if _, ok := hook(value).(Validator); ok {
+		m = append(m, field{fieldValue: &value, path: n, validator: true})
+	}
+	return m
+}
+
+// rollNested appends meta of nested structure or structure referenced by pointer. If pointer is nil, new structure
+// is allocated but the pointer is set only if at least one of env variables exists; defaults of nested fields are
+// applied then. Otherwise pointer stays nil and requirements of nested fields are not checked; errors of tags are reported anyway. Nil
+// pointer to the structure which is already being rolled is not followed, so recursive types stay nil
+func rollNested(m meta, value reflect.Value, n, prefix string, o *options) meta {
+	if value.Kind() == reflect.Struct {
+		return roll(m, value, n, prefix, o)
+	}
+	if !value.IsNil() {
+		return roll(m, value.Elem(), n, prefix, o)
+	}
+	if o.rolling[value.Type().Elem()] > 0 {
+		return m
+	}
+	p := reflect.New(value.Type().Elem())
+	l := len(m)
+	m = roll(m, p.Elem(), n, prefix, o)
+	if present(m[l:]) {
+		settable(value).Set(p)
+		return m
+	}
+	failed := m[:l]
+	for _, v := range m[l:] {
+		var tagErr *TagError
+		if errors.As(v.err, &tagErr) {
+			failed = append(failed, field{fieldName: v.fieldName, path: v.path, err: v.err})
 		}
 	}
-	return m, err
+	return failed
 }
 
-// parseTag, retrieves env info and metadata
-func parseTag(tag, prefix string) (e env, err error) {
-	var def, req, protected strTag
-	var tagName = getTagName(tag)
-	req, err = getTagProperty(tag, "require")
-	if err != nil {
-		return
+// rollSlice appends meta of slice of structures. Slice elements are bound from indexed env variables, e.g.
+// UPSTREAM_0_URL, UPSTREAM_1_URL. The slice is allocated with one element per discovered index
+func rollSlice(m meta, value reflect.Value, n string, sf structField, prefix string, o *options) meta {
+	var idx []int
+	var err error
+	// slice itself is not bound, the field carries errors of the slice only
+	failed := func(err error) meta {
+		return append(m, field{fieldName: n, path: n, err: err})
 	}
-	def, err = getTagProperty(tag, "default")
-	if err != nil {
-		return
+	if sf.noprefix {
+		prefix = o.unprefixed(prefix)
 	}
-	protected, err = getTagProperty(tag, "protected")
-	if err != nil {
-		return
+	name := o.join(prefix, sf.env.tagName)
+	if sf.env.protected.isTrue() && !value.IsNil() {
+		return m
 	}
-	envName := getEnvName(tagName, prefix)
-	value, exists := os.LookupEnv(envName)
-	e = env{
-		name:      envName,
-		tagName:   tagName,
-		value:     value,
-		req:       req,
-		def:       def,
-		protected: protected,
-		present:   exists,
+	if idx, err = indices(n, name, value.Type().Elem(), o); err != nil {
+		return failed(err)
 	}
-	return
+	if len(idx) == 0 {
+		if sf.env.req.value == "true" {
+			return failed(&MissingError{Field: n, Name: name})
+		}
+		settable(value).Set(reflect.Zero(value.Type()))
+		return m
+	}
+	s := reflect.MakeSlice(value.Type(), len(idx), len(idx))
+	for _, i := range idx {
+		m = rollNested(m, s.Index(i), fmt.Sprintf("%s[%d]", n, i), o.join(name, strconv.Itoa(i)), o)
+	}
+	settable(value).Set(s)
+	return m
 }
 
-func getEnvName(envName, prefix string) string {
-	if prefix != "" {
-		return fmt.Sprintf("%s_%s", prefix, envName)
+// indices returns sorted indices of env variables with given prefix; e.g. [0,1] for UPSTREAM_0_URL and
+// UPSTREAM_1_URL. Returns VariableError naming the first missing index if indices are not contiguous. Indices are
+// probed if lookuper doesn't implement Enumerator
+func indices(path, prefix string, elem reflect.Type, o *options) (idx []int, err error) {
+	found := map[int]bool{}
+	enumerator, ok := o.lookuper.(Enumerator)
+	if !ok {
+		return probe(prefix, elem, o), nil
 	}
-	return envName
+	for _, name := range enumerator.Keys() {
+		if !strings.HasPrefix(name, prefix+o.separator) {
+			continue
+		}
+		rest := strings.TrimPrefix(name, prefix+o.separator)
+		end := strings.Index(rest, o.separator)
+		if end <= 0 || strings.TrimLeft(rest[:end], "0123456789") != "" {
+			continue
+		}
+		var i int
+		if i, err = strconv.Atoi(rest[:end]); err != nil {
+			return nil, &VariableError{Field: path, Name: name, Err: fmt.Errorf("invalid index of %s: %w", name, err)}
+		}
+		found[i] = true
+	}
+	for i := range found {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	for i, v := range idx {
+		if i != v {
+			name := o.join(prefix, strconv.Itoa(i))
+			return nil, &VariableError{Field: path, Name: name, Err: fmt.Errorf("%s is missing, found indices %v", name, idx)}
+		}
+	}
+	return idx, nil
 }
 
-func getTagName(tag string) string {
-	return regexp.MustCompile("[a-zA-Z_]+[a-zA-Z0-9_]*").FindString(tag)
+// probe returns indices of slice elements by rolling element of type elem at index 0, 1, ... until none of its
+// variables exists; e.g. [0,1] for UPSTREAM_0_URL and UPSTREAM_1_PORT. Gaps in indices can't be detected
+func probe(prefix string, elem reflect.Type, o *options) (idx []int) {
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	for i := 0; ; i++ {
+		scratch := rollNested(nil, reflect.New(elem).Elem(), "", o.join(prefix, strconv.Itoa(i)), o)
+		if !present(scratch) {
+			return
+		}
+		idx = append(idx, i)
+	}
 }
 
-// parses value from env tag and returns <tag value, tag value exists, error>
-func getTagProperty(tag, t string) (r strTag, err error) {
-	const arr = `\[\w*\s*\!*\@*\#*\$*\%*\^*\&*\**\(*\)*\_*\-*\+*\<*\>*\?*\~*\=*\,*\.*\/*\{*\}*\|*\;*\:*\/*\'*\"*\/*\\*`
-	const scalar = `\[*\]*\w*\s*\!*\@*\#*\$*\%*\^*\&*\**\(*\)*\_*\-*\+*\<*\>*\?*\~*\=*\.*\/*\{*\}*\|*\;*\:*\/*\'*\"*\/*\\*`
-	r = strTag{}
-	var findRegex, removeRegex *regexp.Regexp
-	//	findRegex, err = regexp.Compile(",\\s*" + t + "\\s*=((\\s*([\\[\\w*\\,*\\.*\\s*\\-*])*\\])|(\\s*\\w*\\.*\\-*)*)")
-	findRegex, err = regexp.Compile(",\\s*" + t + "\\s*=((\\s*\\[[" + arr + "]*\\])|(" + scalar + ")*)")
-	if err != nil {
-		err = fmt.Errorf("ivalid %s", t)
-		return
+// present returns true if at least one of env variables of meta exists
+func present(m meta) bool {
+	for _, v := range m {
+		if !v.validator && v.env.present {
+			return true
+		}
 	}
-	removeRegex, err = regexp.Compile(",\\s*" + t + "\\s*=\\s*")
-	if err != nil {
-		err = fmt.Errorf("ivalid %s", t)
-		return
+	return false
+}
+
+// isNested returns true if t is structure or pointer to structure which is not bound as a single value
+func isNested(t reflect.Type, o *options) bool {
+	if _, custom := o.parser(t); custom {
+		return false
 	}
-	match := findRegex.FindString(tag)
-	if match == "" {
-		return
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		if _, custom := o.parser(t); custom {
+			return false
+		}
 	}
-	remove := removeRegex.FindString(strings.ToLower(tag))
-	r.value = strings.ReplaceAll(match, remove, "")
-	r.exists = true
+	return t.Kind() == reflect.Struct
+}
+
+// parseTag resolves name and value of env variable of the field; options of the tag are taken from the template
+// cached in structField
+func parseTag(sf structField, path, prefix string, o *options) (e env, err error) {
+	def, file, expansion := sf.env.def, sf.file, sf.expand
+	if sf.noprefix {
+		prefix = o.unprefixed(prefix)
+	}
+	var value, source string
+	var exists bool
+	envName := o.join(prefix, sf.env.tagName)
+	// the first name or alias which exists supplies the value
+	for i, alias := range sf.tag.names {
+		source = envName
+		if i > 0 {
+			source = o.join(prefix, alias)
+		}
+		value, exists = o.lookuper.LookupEnv(source)
+		if file.isTrue() || (!file.exists && o.files) {
+			value, exists, err = lookupFile(source, value, exists, o)
+			if err != nil {
+				return e, &VariableError{Field: path, Name: source, Err: err}
+			}
+		}
+		if exists {
+			break
+		}
+	}
+	if !exists {
+		source = ""
+	}
+	if expansion.isTrue() || (!expansion.exists && o.expand) {
+		if value, def, err = expandValues(envName, value, exists, def, o); err != nil {
+			return e, &VariableError{Field: path, Name: envName, Err: err}
+		}
+	}
+	e = sf.env
+	e.name, e.source, e.value, e.def, e.present = envName, source, value, def, exists
 	return
 }
 
+// lookupFile reads value of env variable from file referenced by <NAME>_FILE variable, in case <NAME> doesn't exist.
+// Trailing line breaks are trimmed from the file content
+func lookupFile(name, value string, exists bool, o *options) (string, bool, error) {
+	fileName := name + "_FILE"
+	path, fileExists := o.lookuper.LookupEnv(fileName)
+	if !fileExists {
+		return value, exists, nil
+	}
+	if exists {
+		return "", false, fmt.Errorf("both %s and %s are set", name, fileName)
+	}
+	b, err := o.readFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("can't read %s from file %s: %w", name, path, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), true, nil
+}
+
+// expandValues expands references to variables in env value, or in default value if the variable doesn't exist.
+// The default is not expanded if it is not used, so ${VAR:?message} fails only if the default is needed
+func expandValues(name, value string, exists bool, def strTag, o *options) (string, strTag, error) {
+	var err error
+	if exists {
+		if value, err = expand(name, value, o.lookuper); err != nil {
+			return "", def, fmt.Errorf("can't expand %s: %w", name, err)
+		}
+	}
+	if def.exists && !exists {
+		if def.value, err = expand(name, def.value, o.lookuper); err != nil {
+			return "", def, fmt.Errorf("can't expand default value of %s: %w", name, err)
+		}
+	}
+	return value, def, nil
+}
+
+// autoName prepends name of env variable derived from field name to the tag, unless the tag contains the name
+func autoName(tag, fieldName string) string {
+	if strings.TrimSpace(strings.SplitN(tag, ",", 2)[0]) != "" {
+		return tag
+	}
+	if tag == "" {
+		return snakeCase(fieldName)
+	}
+	return snakeCase(fieldName) + tag
+}
+
+// snakeCase converts field name to UPPER_SNAKE_CASE; e.g. MaxIdleConns to MAX_IDLE_CONNS and HTTPPort to HTTP_PORT
+func snakeCase(s string) string {
+	var b strings.Builder
+	r := []rune(s)
+	for i := range r {
+		if i > 0 && unicode.IsUpper(r[i]) {
+			prev := r[i-1]
+			nextLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r[i]))
+	}
+	return b.String()
+}
+
+// nestedPrefix returns prefix of nested structure. By default the structure inherits prefix of the parent and adds
+// its name; prefix=replace drops prefix of the parent and prefix=ignore drops both. The global prefix is kept unless
+// noprefix=true
+func nestedPrefix(sf structField, path, prefix string, o *options) (nested string, err error) {
+	mode, name := sf.prefix, sf.env.tagName
+	root := o.prefix
+	if sf.noprefix {
+		prefix = o.unprefixed(prefix)
+		root = ""
+	}
+	switch mode.value {
+	case "", "inherit":
+		return o.join(prefix, name), nil
+	case "replace":
+		return o.join(root, name), nil
+	case "ignore":
+		return root, nil
+	}
+	return "", &TagError{Field: path, Tag: sf.env.tag, Column: mode.column,
+		Message: fmt.Sprintf("invalid prefix=%s, expected inherit, replace or ignore", mode.value)}
+}
+
 func (t strTag) asStringSlice() (s []string) {
 	if !t.exists {
 		return
@@ -357,6 +655,16 @@ func (t strTag) asStringSlice() (s []string) {
 	return
 }
 
+// asMap returns raw key-value pairs of default map literal; e.g. {a:1,b:2}
+func (t strTag) asMap(separator, kvSeparator string) (m map[string]string, err error) {
+	if !t.exists {
+		return
+	}
+	envdef := strings.TrimSpace(t.value)
+	envdef = strings.TrimSuffix(strings.TrimPrefix(envdef, "{"), "}")
+	return splitPairs(envdef, separator, kvSeparator)
+}
+
 func (t strTag) isTrue() bool {
 	if !t.exists {
 		return false
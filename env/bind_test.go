@@ -18,10 +18,17 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package env
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -72,8 +79,10 @@ func TestInvalidEnvVar(t *testing.T) {
 	tok := &token{ID: 5}
 	err := Bind(tok)
 	// assert
-	assert.NoError(t, err)
-	assert.Equal(t, 0, tok.ID)
+	var tagErr *TagError
+	assert.True(t, errors.As(err, &tagErr))
+	assert.Equal(t, 3, tagErr.Column)
+	assert.Equal(t, 5, tok.ID)
 }
 
 func TestUnsupportedDataType(t *testing.T) {
@@ -142,25 +151,29 @@ func TestSetDefaultEmpty(t *testing.T) {
 		Value       string    `env:"TOKEN_VALUE, default="`
 		Ratio       float64   `env:"TOKEN_RATIO, default=0"`
 		Readonly    bool      `env:"TOKEN_READONLY, default=false"`
-		Hours       []int     `env:"TOKEN_HOURS, default[]"` // can't be parsed!
 		URLs        []string  `env:"TOKEN_URLS, default=[]"`
 		Enabled     []bool    `env:"TOKEN_BOOLS, default=[]"`
 		Coordinates []float64 `env:"TOKEN_COORDINATES, default=[]"`
+		Hours       []int     `env:"TOKEN_HOURS, default[]"` // can't be parsed!
 	}
 	tok := &token{}
 	// act
 	err := Bind(tok)
 
 	// assert
-	assert.NoError(t, err)
+	var tagErr *TagError
+	assert.True(t, errors.As(err, &tagErr))
+	assert.Equal(t, "token.Hours", tagErr.Field)
+	assert.Equal(t, 21, tagErr.Column)
+	assert.EqualError(t, err, "invalid tag of token.Hours at column 21: missing '=' after default")
 	assert.Equal(t, 0, tok.ID)
 	assert.Equal(t, "", tok.Value)
 	assert.Equal(t, 0., tok.Ratio)
 	assert.Equal(t, false, tok.Readonly)
-	assert.Equal(t, []int(nil), tok.Hours)
 	assert.Equal(t, []string{}, tok.URLs)
 	assert.Equal(t, []bool{}, tok.Enabled)
 	assert.Equal(t, []float64{}, tok.Coordinates)
+	assert.Equal(t, []int(nil), tok.Hours)
 }
 
 func TestInvalidValue(t *testing.T) {
@@ -1066,6 +1079,72 @@ func TestTypeUint64(t *testing.T) {
 	assert.Equal(t, uint64(20), tok.doesntExists)
 }
 
+func TestLosslessIntegers(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(envInt, "9007199254740993")
+	_ = os.Setenv(envInt2, "18446744073709551615")
+	_ = os.Setenv(envIntSlice, "9007199254740993, -9223372036854775808")
+	type token struct {
+		I64  int64   `env:"ENV_INT"`
+		U64  uint64  `env:"ENV_INT2"`
+		I64s []int64 `env:"ENV_INT_SLICE"`
+		Def  uint64  `env:"ENV_INT3, default=9007199254740995"`
+	}
+	tok := &token{}
+	err := Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), tok.I64)
+	assert.Equal(t, uint64(18446744073709551615), tok.U64)
+	assert.Equal(t, []int64{9007199254740993, -9223372036854775808}, tok.I64s)
+	assert.Equal(t, uint64(9007199254740995), tok.Def)
+}
+
+func TestInvalidIntegers(t *testing.T) {
+	defer cleanup()
+	type tokenInt struct {
+		I int `env:"ENV_INT"`
+	}
+	type tokenInt8 struct {
+		I int8 `env:"ENV_INT"`
+	}
+	type tokenUint struct {
+		U uint `env:"ENV_INT"`
+	}
+	type tokenUint8Slice struct {
+		U []uint8 `env:"ENV_INT"`
+	}
+	type tokenDefault struct {
+		I int16 `env:"ENV_INT3, default=40000"`
+	}
+	type tokenSliceDefault struct {
+		U []uint `env:"ENV_INT3, default=[1,-1]"`
+	}
+	tests := []struct {
+		name    string
+		value   string
+		testee  interface{}
+		message string
+	}{
+		{"fraction to int", "1.9", &tokenInt{}, "floating point value can't be set to int"},
+		{"overflow int8", "300", &tokenInt8{}, "value is out of range of int8"},
+		{"underflow int8", "-129", &tokenInt8{}, "value is out of range of int8"},
+		{"negative to uint", "-1", &tokenUint{}, "negative value can't be set to uint"},
+		{"overflow uint8 slice", "1,256", &tokenUint8Slice{}, "value is out of range of uint8"},
+		{"invalid int", "abc", &tokenInt{}, "invalid syntax of int"},
+		{"overflow default", "", &tokenDefault{}, "value is out of range of int16"},
+		{"negative default", "", &tokenSliceDefault{}, "negative value can't be set to uint"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_ = os.Setenv(envInt, test.value)
+			err := Bind(test.testee)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.message)
+			assert.Contains(t, err.Error(), "ENV_INT")
+		})
+	}
+}
+
 func TestTypeString(t *testing.T) {
 	cleanup()
 	_ = os.Setenv(envInt, "")
@@ -1372,6 +1451,1258 @@ func TestTypeBoolSlice(t *testing.T) {
 	assert.Equal(t, []bool{true, true}, tok.doesntExists)
 }
 
+func TestTypeDuration(t *testing.T) {
+	cleanup()
+	_ = os.Setenv(envInt, "")
+	_ = os.Setenv(envInt2, "1m30s")
+	type token struct {
+		empty        time.Duration `env:"ENV_INT"`
+		filled       time.Duration `env:"ENV_INT2, require=true"`
+		protected    time.Duration `env:"ENV_INT, protected=true"`
+		doesntExists time.Duration `env:"ENV_INT3, default=30s"`
+	}
+
+	tok := &token{protected: time.Hour}
+	err := Bind(tok)
+	assert.Error(t, err)
+	_ = os.Setenv(envInt, "250ms")
+	err = Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, tok.empty)
+	assert.Equal(t, 90*time.Second, tok.filled)
+	assert.Equal(t, time.Hour, tok.protected)
+	assert.Equal(t, 30*time.Second, tok.doesntExists)
+
+	_ = os.Setenv(envInt, "30")
+	err = Bind(&token{})
+	assert.Error(t, err)
+	type invalidDefault struct {
+		d time.Duration `env:"ENV_INT3, default=5 minutes"`
+	}
+	err = Bind(&invalidDefault{})
+	assert.Error(t, err)
+}
+
+func TestTypeDurationSlice(t *testing.T) {
+	cleanup()
+	_ = os.Setenv(envInt, "")
+	_ = os.Setenv(envInt2, "1s, 2m,3h")
+	type token struct {
+		empty        []time.Duration `env:"ENV_INT"`
+		filled       []time.Duration `env:"ENV_INT2, require=true"`
+		protected    []time.Duration `env:"ENV_INT, protected=true"`
+		doesntExists []time.Duration `env:"ENV_INT3, default=[20s, 1h]"`
+		emptyDefault []time.Duration `env:"ENV_INT3, default=[]"`
+		none         []time.Duration `env:"ENV_INT3"`
+	}
+
+	tok := &token{protected: []time.Duration{time.Minute}}
+	err := Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{}, tok.empty)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute, 3 * time.Hour}, tok.filled)
+	assert.Equal(t, []time.Duration{time.Minute}, tok.protected)
+	assert.Equal(t, []time.Duration{20 * time.Second, time.Hour}, tok.doesntExists)
+	assert.Equal(t, []time.Duration{}, tok.emptyDefault)
+	assert.Nil(t, tok.none)
+
+	_ = os.Setenv(envInt2, "1s,invalid")
+	err = Bind(&token{})
+	assert.Error(t, err)
+}
+
+// logLevel implements encoding.TextUnmarshaler
+type logLevel int
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %s", text)
+	}
+	return nil
+}
+
+func TestTextUnmarshaler(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(envString, "10.0.0.1")
+	_ = os.Setenv(envStringSlice, "10.0.0.1, ::1")
+	_ = os.Setenv(envInt, "2021-10-01T10:00:00Z")
+	_ = os.Setenv(envBool, "error")
+	type token struct {
+		IP        net.IP     `env:"ENV_STRING"`
+		IPs       []net.IP   `env:"ENV_STRING_SLICE"`
+		Time      time.Time  `env:"ENV_INT"`
+		Level     logLevel   `env:"ENV_BOOL"`
+		Levels    []logLevel `env:"ENV_INT3, default=[info, debug]"`
+		Big       big.Int    `env:"ENV_INT3, default=18446744073709551616"`
+		Protected logLevel   `env:"ENV_BOOL, protected=true"`
+		Empty     []logLevel `env:"ENV_INT3"`
+		Nested    struct {
+			Time time.Time `env:"TIME, default=2020-01-01T00:00:00Z"`
+		} `env:"NESTED"`
+	}
+	tok := &token{Protected: 1}
+	err := Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("10.0.0.1"), tok.IP)
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("::1")}, tok.IPs)
+	assert.Equal(t, time.Date(2021, 10, 1, 10, 0, 0, 0, time.UTC), tok.Time)
+	assert.Equal(t, logLevel(2), tok.Level)
+	assert.Equal(t, []logLevel{1, 0}, tok.Levels)
+	assert.Equal(t, "18446744073709551616", tok.Big.String())
+	assert.Equal(t, logLevel(1), tok.Protected)
+	assert.Nil(t, tok.Empty)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), tok.Nested.Time)
+}
+
+func TestTextUnmarshalerInvalidValue(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(envBool, "verbose")
+	_ = os.Setenv(envBoolSlice, "info,verbose")
+	type tokenValue struct {
+		Level logLevel `env:"ENV_BOOL"`
+	}
+	type tokenDefault struct {
+		Level logLevel `env:"ENV_INT3, default=verbose"`
+	}
+	type tokenSlice struct {
+		Levels []logLevel `env:"ENV_BOOL_SLICE"`
+	}
+	type tokenSliceDefault struct {
+		Levels []logLevel `env:"ENV_INT3, default=[info,verbose]"`
+	}
+	assert.Error(t, Bind(&tokenValue{}))
+	assert.Error(t, Bind(&tokenDefault{}))
+	assert.Error(t, Bind(&tokenSlice{}))
+	assert.Error(t, Bind(&tokenSliceDefault{}))
+}
+
+// rgb doesn't implement any interface and can be bound by registered decoder only
+type rgb struct {
+	R, G, B uint8
+}
+
+func parseRGB(s string) (interface{}, error) {
+	var c rgb
+	_, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	return c, err
+}
+
+func TestDecoder(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(envString, "#ff0080")
+	_ = os.Setenv(envStringSlice, "#000000, #ffffff")
+	_ = os.Setenv(envBool, "verbose")
+	type token struct {
+		Color     rgb      `env:"ENV_STRING"`
+		Colors    []rgb    `env:"ENV_STRING_SLICE"`
+		Default   rgb      `env:"ENV_INT3, default=#010203"`
+		Defaults  []rgb    `env:"ENV_INT3, default=[#010101,#020202]"`
+		Protected rgb      `env:"ENV_STRING, protected=true"`
+		Level     logLevel `env:"ENV_BOOL"`
+	}
+	verbose := func(s string) (interface{}, error) {
+		return logLevel(-1), nil
+	}
+	tok := &token{Protected: rgb{1, 1, 1}}
+	err := BindWithOptions(tok, WithDecoder(reflect.TypeOf(rgb{}), parseRGB), WithDecoder(reflect.TypeOf(logLevel(0)), verbose))
+	assert.NoError(t, err)
+	assert.Equal(t, rgb{0xff, 0, 0x80}, tok.Color)
+	assert.Equal(t, []rgb{{0, 0, 0}, {0xff, 0xff, 0xff}}, tok.Colors)
+	assert.Equal(t, rgb{1, 2, 3}, tok.Default)
+	assert.Equal(t, []rgb{{1, 1, 1}, {2, 2, 2}}, tok.Defaults)
+	assert.Equal(t, rgb{1, 1, 1}, tok.Protected)
+	assert.Equal(t, logLevel(-1), tok.Level)
+
+	// without decoder is rgb considered as nested structure, logLevel is unmarshalled
+	err = Bind(&token{})
+	assert.Error(t, err)
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	defer cleanup()
+	defer RegisterDecoder(reflect.TypeOf(rgb{}), nil)
+	_ = os.Setenv(envString, "#ff0080")
+	_ = os.Setenv(envStringSlice, "#000000,invalid")
+	type token struct {
+		Color rgb `env:"ENV_STRING"`
+	}
+	type tokenSlice struct {
+		Colors []rgb `env:"ENV_STRING_SLICE"`
+	}
+	type tokenInt struct {
+		Number int `env:"ENV_STRING"`
+	}
+	RegisterDecoder(reflect.TypeOf(rgb{}), parseRGB)
+	tok := &token{}
+	err := Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, rgb{0xff, 0, 0x80}, tok.Color)
+	err = Bind(&tokenSlice{})
+	assert.Error(t, err)
+
+	// decoder returns invalid type
+	err = BindWithOptions(&tokenInt{}, WithDecoder(reflect.TypeOf(0), parseRGB))
+	assert.Error(t, err)
+}
+
+func TestMap(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(envString, "team:core, tier : gold")
+	_ = os.Setenv(envInt, "eu-west-1=10;us-east-1=-5")
+	_ = os.Setenv(envBool, "")
+	type token struct {
+		Labels    map[string]string        `env:"ENV_STRING"`
+		Weights   map[string]int8          `env:"ENV_INT, separator=;, kvseparator=="`
+		Empty     map[string]bool          `env:"ENV_BOOL"`
+		Timeouts  map[string]time.Duration `env:"ENV_INT3, default={read:1s, write:2m}"`
+		Floats    map[string]float64       `env:"ENV_INT3, default={a:1.5;b:-2}, separator=;"`
+		Levels    map[string]logLevel      `env:"ENV_INT3, default={app:debug,db:error}"`
+		EmptyDef  map[string]uint          `env:"ENV_INT3, default={}"`
+		None      map[string]uint          `env:"ENV_INT3"`
+		Protected map[string]string        `env:"ENV_STRING, protected=true"`
+	}
+	tok := &token{Protected: map[string]string{"a": "b"}}
+	err := Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "core", "tier": "gold"}, tok.Labels)
+	assert.Equal(t, map[string]int8{"eu-west-1": 10, "us-east-1": -5}, tok.Weights)
+	assert.Equal(t, map[string]bool{}, tok.Empty)
+	assert.Equal(t, map[string]time.Duration{"read": time.Second, "write": 2 * time.Minute}, tok.Timeouts)
+	assert.Equal(t, map[string]float64{"a": 1.5, "b": -2}, tok.Floats)
+	assert.Equal(t, map[string]logLevel{"app": 0, "db": 2}, tok.Levels)
+	assert.Equal(t, map[string]uint{}, tok.EmptyDef)
+	assert.Nil(t, tok.None)
+	assert.Equal(t, map[string]string{"a": "b"}, tok.Protected)
+}
+
+func TestInvalidMap(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(envInt, "a:1,b:x")
+	_ = os.Setenv(envString, "a:1,b")
+	_ = os.Setenv(envBool, "a:true,a:false")
+	type tokenValue struct {
+		M map[string]int `env:"ENV_INT"`
+	}
+	type tokenPair struct {
+		M map[string]int `env:"ENV_STRING"`
+	}
+	type tokenDuplicate struct {
+		M map[string]bool `env:"ENV_BOOL"`
+	}
+	type tokenDefault struct {
+		M map[string]uint `env:"ENV_INT3, default={a:1,b:-1}"`
+	}
+	type tokenUnsupported struct {
+		M map[string][]int `env:"ENV_INT"`
+	}
+	err := Bind(&tokenValue{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "key 'b'")
+	err = Bind(&tokenPair{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'b'")
+	err = Bind(&tokenDuplicate{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate key 'a'")
+	err = Bind(&tokenDefault{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "key 'b'")
+	err = Bind(&tokenUnsupported{})
+	assert.EqualError(t, err, "unsupported type map[string][]int of tokenUnsupported.M")
+
+	// unsupported value types are reported whether the variable is set or not
+	type endpoint struct {
+		URL string `env:"URL"`
+	}
+	type tokenUnset struct {
+		Slices    map[string][]int    `env:"UNSET_SLICES"`
+		Endpoints map[string]endpoint `env:"UNSET_ENDPOINTS"`
+		Keys      map[int]string      `env:"UNSET_KEYS"`
+	}
+	var bindErr *BindError
+	err = BindFrom(MapLookuper{}, &tokenUnset{})
+	assert.True(t, errors.As(err, &bindErr))
+	assert.Len(t, bindErr.Errors, 3)
+	for _, e := range bindErr.Errors {
+		assert.IsType(t, &UnsupportedTypeError{}, e)
+	}
+	var unsupported *UnsupportedTypeError
+	assert.True(t, errors.As(bindErr.Errors[1], &unsupported))
+	assert.Equal(t, &UnsupportedTypeError{Field: "tokenUnset.Endpoints", Name: "UNSET_ENDPOINTS",
+		Type: reflect.TypeOf(map[string]endpoint{})}, unsupported)
+}
+
+func TestPointer(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(envInt, "0")
+	_ = os.Setenv(envString, "")
+	_ = os.Setenv(envBool, "false")
+	_ = os.Setenv(envFloat64, "1m")
+	_ = os.Setenv(envIntSlice, "1,2")
+	type token struct {
+		Port       *int            `env:"ENV_INT"`
+		Name       *string         `env:"ENV_STRING"`
+		Enabled    *bool           `env:"ENV_BOOL"`
+		Timeout    *time.Duration  `env:"ENV_FLOAT64"`
+		Ints       *[]int          `env:"ENV_INT_SLICE"`
+		Time       *time.Time      `env:"ENV_INT3, default=2021-01-01T00:00:00Z"`
+		Default    *uint8          `env:"ENV_INT3, default=10"`
+		Unset      *int            `env:"ENV_INT3"`
+		Overridden *string         `env:"ENV_INT3"`
+		Protected  *int            `env:"ENV_INT, protected=true"`
+		Zero       *int            `env:"ENV_INT, protected=true"`
+		Labels     *map[string]int `env:"ENV_INT3, default={a:1}"`
+	}
+	s := "value"
+	p := 0
+	tok := &token{Overridden: &s, Protected: &p}
+	err := Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *tok.Port)
+	assert.Equal(t, "", *tok.Name)
+	assert.Equal(t, false, *tok.Enabled)
+	assert.Equal(t, time.Minute, *tok.Timeout)
+	assert.Equal(t, []int{1, 2}, *tok.Ints)
+	assert.Equal(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), *tok.Time)
+	assert.Equal(t, uint8(10), *tok.Default)
+	assert.Nil(t, tok.Unset)
+	assert.Nil(t, tok.Overridden)
+	assert.Equal(t, &p, tok.Protected)
+	assert.Equal(t, 0, *tok.Zero)
+	assert.Equal(t, map[string]int{"a": 1}, *tok.Labels)
+
+	_ = os.Setenv(envInt, "x")
+	err = Bind(&token{})
+	assert.Error(t, err)
+}
+
+func TestPointerToStructure(t *testing.T) {
+	defer cleanup()
+	_ = os.Setenv(primaryEndpointURL, "https://ep1.cloud.example.com")
+	type Endpoint struct {
+		URL  string `env:"ENDPOINT_URL, require=true"`
+		Port *int   `env:"ENDPOINT_PORT"`
+	}
+	type token struct {
+		Primary   *Endpoint `env:"PRIMARY"`
+		Failover  *Endpoint `env:"FAILOVER"`
+		Existing  *Endpoint `env:"EXISTING"`
+		Defaulted *struct {
+			Retries int           `env:"RETRIES, default=3"`
+			Backoff time.Duration `env:"BACKOFF, default=1s"`
+		} `env:"DEFAULTED"`
+		// untagged pointers are not followed
+		Untagged *Endpoint
+	}
+	tok := &token{Existing: &Endpoint{URL: "keep"}}
+	err := Bind(tok)
+	assert.EqualError(t, err, "EXISTING_ENDPOINT_URL is required")
+
+	tok = &token{}
+	err = Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ep1.cloud.example.com", tok.Primary.URL)
+	assert.Nil(t, tok.Primary.Port)
+	assert.Nil(t, tok.Failover)
+	assert.Nil(t, tok.Existing)
+	// defaults don't allocate pointer
+	assert.Nil(t, tok.Defaulted)
+	assert.Nil(t, tok.Untagged)
+
+	_ = os.Setenv(failoverEndpointURL, "https://ep2.cloud.example.com")
+	_ = os.Setenv("FAILOVER_ENDPOINT_PORT", "8080")
+	defer func() { _ = os.Unsetenv("FAILOVER_ENDPOINT_PORT") }()
+	tok = &token{}
+	err = Bind(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ep2.cloud.example.com", tok.Failover.URL)
+	assert.Equal(t, 8080, *tok.Failover.Port)
+
+	// defaults are applied once the pointer is allocated
+	tok = &token{}
+	err = BindFrom(MapLookuper{primaryEndpointURL: "https://ep1.cloud.example.com", "DEFAULTED_RETRIES": "5"}, tok)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, tok.Defaulted.Retries)
+	assert.Equal(t, time.Second, tok.Defaulted.Backoff)
+}
+
+func TestRecursiveStructure(t *testing.T) {
+	t.Parallel()
+	type Node struct {
+		Name string `env:"NAME"`
+		Next *Node
+	}
+	type Tree struct {
+		Name   string `env:"NAME"`
+		Parent *Tree  `env:"PARENT"`
+		Left   *Tree  `env:"LEFT"`
+	}
+	node := &Node{}
+	err := BindFrom(MapLookuper{"NAME": "head", "NEXT_NAME": "tail"}, node)
+	assert.NoError(t, err)
+	assert.Equal(t, &Node{Name: "head"}, node)
+
+	// nil pointers to the structure which is being bound stay nil
+	node = &Node{}
+	err = BindWithOptions(node, WithLookuper(MapLookuper{"NAME": "head", "NEXT_NAME": "tail"}), AutoNames())
+	assert.NoError(t, err)
+	assert.Equal(t, &Node{Name: "head"}, node)
+
+	// existing pointers are followed
+	tree := &Tree{Left: &Tree{}}
+	err = BindFrom(MapLookuper{"NAME": "root", "LEFT_NAME": "left", "PARENT_NAME": "parent"}, tree)
+	assert.NoError(t, err)
+	assert.Equal(t, &Tree{Name: "root", Left: &Tree{Name: "left"}}, tree)
+}
+
+func TestSliceOfStructures(t *testing.T) {
+	defer cleanup()
+	type Endpoint struct {
+		URL     string `env:"URL, require=true"`
+		Port    int    `env:"PORT, default=80"`
+		Retries *int   `env:"RETRIES"`
+	}
+	type token struct {
+		Upstreams []Endpoint  `env:"UPSTREAM"`
+		Pointers  []*Endpoint `env:"UPSTREAM"`
+		Empty     []Endpoint  `env:"DOWNSTREAM"`
+		Protected []Endpoint  `env:"UPSTREAM, protected=true"`
+		Untagged  []Endpoint
+	}
+	setEnv(map[string]string{
+		"UPSTREAM_0_URL":     "https://ep0.example.com",
+		"UPSTREAM_1_URL":     "https://ep1.example.com",
+		"UPSTREAM_1_PORT":    "8080",
+		"UPSTREAM_1_RETRIES": "3",
+	})
+	defer unsetEnv("UPSTREAM_0_URL", "UPSTREAM_1_URL", "UPSTREAM_1_PORT", "UPSTREAM_1_RETRIES", "UPSTREAM_2_PORT", "UPSTREAM_3_URL")
+	tok := &token{Empty: []Endpoint{{}}, Protected: []Endpoint{{URL: "keep"}}, Untagged: []Endpoint{{URL: "keep"}}}
+	err := Bind(tok)
+	assert.NoError(t, err)
+	retries := 3
+	assert.Equal(t, []Endpoint{{URL: "https://ep0.example.com", Port: 80}, {URL: "https://ep1.example.com", Port: 8080, Retries: &retries}},
+		tok.Upstreams)
+	assert.Equal(t, []*Endpoint{{URL: "https://ep0.example.com", Port: 80}, {URL: "https://ep1.example.com", Port: 8080, Retries: &retries}},
+		tok.Pointers)
+	assert.Nil(t, tok.Empty)
+	assert.Equal(t, []Endpoint{{URL: "keep"}}, tok.Protected)
+	assert.Equal(t, []Endpoint{{URL: "keep"}}, tok.Untagged)
+
+	// missing required value of third element, reported for each of three fields bound from UPSTREAM
+	_ = os.Setenv("UPSTREAM_2_PORT", "90")
+	err = Bind(&token{})
+	assert.EqualError(t, err, "UPSTREAM_2_URL is required; UPSTREAM_2_URL is required; UPSTREAM_2_URL is required")
+
+	// gap in indices
+	_ = os.Unsetenv("UPSTREAM_2_PORT")
+	_ = os.Setenv("UPSTREAM_3_URL", "https://ep3.example.com")
+	err = Bind(&token{})
+	assert.EqualError(t, err, "UPSTREAM_2 is missing, found indices [0 1 3]; "+
+		"UPSTREAM_2 is missing, found indices [0 1 3]; UPSTREAM_2 is missing, found indices [0 1 3]")
+	var varErr *VariableError
+	assert.True(t, errors.As(err, &varErr))
+	assert.Equal(t, "token.Upstreams", varErr.Field)
+	assert.Equal(t, "UPSTREAM_2", varErr.Name)
+
+	type required struct {
+		Endpoints []Endpoint `env:"DOWNSTREAM, require=true"`
+	}
+	err = Bind(&required{})
+	assert.EqualError(t, err, "DOWNSTREAM is required")
+}
+
+// lookuper implements Lookuper but not Enumerator
+type lookuper map[string]string
+
+func (l lookuper) LookupEnv(key string) (v string, ok bool) {
+	v, ok = l[key]
+	return
+}
+
+func TestBindFrom(t *testing.T) {
+	t.Parallel()
+	type Endpoint struct {
+		URL string `env:"URL, require=true"`
+	}
+	type token struct {
+		Name      string            `env:"NAME, require=true"`
+		Port      int               `env:"PORT, default=8080"`
+		Enabled   bool              `env:"ENABLED"`
+		Regions   []string          `env:"REGIONS"`
+		Timeouts  []time.Duration   `env:"TIMEOUTS"`
+		Labels    map[string]string `env:"LABELS"`
+		Primary   Endpoint          `env:"PRIMARY"`
+		Upstreams []Endpoint        `env:"UPSTREAM"`
+	}
+	source1 := MapLookuper{
+		"NAME":             "first",
+		"ENABLED":          "true",
+		"REGIONS":          "eu, us",
+		"TIMEOUTS":         "1s,2s",
+		"LABELS":           "a:b",
+		"PRIMARY_URL":      "https://primary.example.com",
+		"UPSTREAM_0_URL":   "https://ep0.example.com",
+		"UPSTREAM_1_URL":   "https://ep1.example.com",
+		"UNRELATED_0_NAME": "x",
+	}
+	source2 := MapLookuper{
+		"NAME":        "second",
+		"PORT":        "9000",
+		"PRIMARY_URL": "https://second.example.com",
+	}
+	tok1, tok2 := &token{}, &token{}
+	err := BindFrom(source1, tok1)
+	assert.NoError(t, err)
+	err = BindFrom(source2, tok2)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "first", tok1.Name)
+	assert.Equal(t, 8080, tok1.Port)
+	assert.True(t, tok1.Enabled)
+	assert.Equal(t, []string{"eu", "us"}, tok1.Regions)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, tok1.Timeouts)
+	assert.Equal(t, map[string]string{"a": "b"}, tok1.Labels)
+	assert.Equal(t, "https://primary.example.com", tok1.Primary.URL)
+	assert.Equal(t, []Endpoint{{URL: "https://ep0.example.com"}, {URL: "https://ep1.example.com"}}, tok1.Upstreams)
+
+	assert.Equal(t, "second", tok2.Name)
+	assert.Equal(t, 9000, tok2.Port)
+	assert.False(t, tok2.Enabled)
+	assert.Nil(t, tok2.Regions)
+	assert.Nil(t, tok2.Upstreams)
+}
+
+func TestBindFromInvalidLookuper(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		Name string `env:"NAME"`
+	}
+	type endpoint struct {
+		URL  string `env:"URL"`
+		Port int    `env:"PORT, default=80"`
+	}
+	type tokenSlice struct {
+		Endpoints []endpoint  `env:"UPSTREAM"`
+		Pointers  []*endpoint `env:"UPSTREAM"`
+	}
+	tok := &token{}
+	err := BindFrom(nil, tok)
+	assert.Error(t, err)
+	err = BindFrom(lookuper{"NAME": "lookuper"}, tok)
+	assert.NoError(t, err)
+	assert.Equal(t, "lookuper", tok.Name)
+	// indices are probed without Enumerator until no variable of the element exists
+	slice := &tokenSlice{}
+	err = BindFrom(lookuper{"UPSTREAM_0_URL": "https://ep0.example.com", "UPSTREAM_1_PORT": "8080",
+		"UPSTREAM_3_URL": "https://ep3.example.com"}, slice)
+	assert.NoError(t, err)
+	assert.Equal(t, []endpoint{{URL: "https://ep0.example.com", Port: 80}, {Port: 8080}}, slice.Endpoints)
+	assert.Equal(t, []*endpoint{{URL: "https://ep0.example.com", Port: 80}, {Port: 8080}}, slice.Pointers)
+	slice = &tokenSlice{}
+	err = BindFrom(lookuper{}, slice)
+	assert.NoError(t, err)
+	assert.Nil(t, slice.Endpoints)
+	type requiredSlice struct {
+		Name      string `env:"NAME"`
+		Endpoints []struct {
+			URL string `env:"URL"`
+		} `env:"UPSTREAM, require=true"`
+	}
+	err = BindFrom(lookuper{"NAME": "lookuper"}, &requiredSlice{})
+	assert.EqualError(t, err, "UPSTREAM is required")
+	assert.ErrorAs(t, err, new(*MissingError))
+}
+
+func TestFileSecrets(t *testing.T) {
+	t.Parallel()
+	fsys := fstest.MapFS{
+		"run/secrets/db":    {Data: []byte("s3cr3t\n")},
+		"run/secrets/token": {Data: []byte("abc\r\n")},
+		"run/secrets/port":  {Data: []byte("5432")},
+	}
+	type token struct {
+		Password string `env:"DB_PASSWORD, file=true"`
+		Token    string `env:"TOKEN"`
+		Port     int    `env:"DB_PORT, file=true, default=3306"`
+		User     string `env:"DB_USER, file=true, default=admin"`
+		Disabled string `env:"DISABLED, file=false"`
+	}
+	source := MapLookuper{
+		"DB_PASSWORD_FILE": "/run/secrets/db",
+		"TOKEN_FILE":       "/run/secrets/token",
+		"DB_PORT_FILE":     "/run/secrets/port",
+		"DISABLED_FILE":    "/run/secrets/db",
+	}
+
+	tok := &token{}
+	err := BindWithOptions(tok, WithLookuper(source), WithFS(fsys))
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", tok.Password)
+	assert.Equal(t, "", tok.Token)
+	assert.Equal(t, 5432, tok.Port)
+	assert.Equal(t, "admin", tok.User)
+
+	tok = &token{}
+	err = BindWithOptions(tok, WithLookuper(source), WithFS(fsys), FileSecrets())
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", tok.Token)
+	assert.Equal(t, "", tok.Disabled)
+
+	// both variables are set
+	source["DB_PASSWORD"] = "password"
+	err = BindWithOptions(&token{}, WithLookuper(source), WithFS(fsys))
+	assert.EqualError(t, err, "both DB_PASSWORD and DB_PASSWORD_FILE are set")
+	var varErr *VariableError
+	assert.True(t, errors.As(err, &varErr))
+	assert.Equal(t, "DB_PASSWORD", varErr.Name)
+
+	// file doesn't exist
+	delete(source, "DB_PASSWORD")
+	source["DB_PASSWORD_FILE"] = "/run/secrets/missing"
+	err = BindWithOptions(&token{}, WithLookuper(source), WithFS(fsys))
+	assert.Error(t, err)
+
+	// reading from operating system
+	path := filepath.Join(t.TempDir(), "db")
+	assert.NoError(t, os.WriteFile(path, []byte("from os\n"), 0600))
+	tok = &token{}
+	err = BindWithOptions(tok, WithLookuper(MapLookuper{"DB_PASSWORD_FILE": path}))
+	assert.NoError(t, err)
+	assert.Equal(t, "from os", tok.Password)
+}
+
+func TestBindError(t *testing.T) {
+	t.Parallel()
+	type Credentials struct {
+		KeyID  string `env:"KEY_ID, require=true"`
+		Secret string `env:"SECRET, require=true"`
+	}
+	type token struct {
+		Name        string            `env:"NAME, require=true"`
+		Port        int               `env:"PORT"`
+		Valid       string            `env:"VALID"`
+		Unsupported chan int          `env:"CHANNEL"`
+		Credentials Credentials       `env:"CREDENTIALS"`
+		Ratio       float64           `env:"RATIO, default=half"`
+		Upstreams   []struct{ X int } `env:"UPSTREAM, require=true"`
+	}
+	source := MapLookuper{
+		"PORT":    "eighty",
+		"VALID":   "valid",
+		"CHANNEL": "1",
+	}
+	for i := 0; i < 10; i++ {
+		tok := &token{}
+		err := BindFrom(source, tok)
+		var bindErr *BindError
+		assert.True(t, errors.As(err, &bindErr))
+		assert.Equal(t, "valid", tok.Valid)
+		assert.Len(t, bindErr.Errors, 7)
+		assert.EqualError(t, bindErr.Errors[0], "NAME is required")
+		assert.Contains(t, bindErr.Errors[1].Error(), "PORT")
+		assert.Contains(t, bindErr.Errors[2].Error(), "unsupported type")
+		assert.EqualError(t, bindErr.Errors[3], "CREDENTIALS_KEY_ID is required")
+		assert.EqualError(t, bindErr.Errors[4], "CREDENTIALS_SECRET is required")
+		assert.Contains(t, bindErr.Errors[5].Error(), "RATIO")
+		assert.EqualError(t, bindErr.Errors[6], "UPSTREAM is required")
+	}
+
+	// wrapped errors are reachable through BindError
+	errInvalid := errors.New("invalid rgb")
+	decoder := WithDecoder(reflect.TypeOf(rgb{}), func(string) (interface{}, error) { return nil, errInvalid })
+	err := BindWithOptions(&struct {
+		Color rgb `env:"COLOR"`
+	}{}, WithLookuper(MapLookuper{"COLOR": "red"}), decoder)
+	assert.True(t, errors.Is(err, errInvalid))
+	err = BindFrom(MapLookuper{"TIMEOUT": "never"}, &struct {
+		Timeout time.Duration `env:"TIMEOUT"`
+	}{})
+	var bindErr *BindError
+	assert.True(t, errors.As(err, &bindErr))
+	assert.Len(t, bindErr.Errors, 1)
+}
+
+type Credentials struct {
+	KeyID   string        `env:"KEY_ID, require=true"`
+	Timeout time.Duration `env:"TIMEOUT, default=soon"`
+}
+
+type Config struct {
+	Credentials Credentials `env:"CREDENTIALS"`
+	Port        int         `env:"PORT"`
+	Channel     chan int    `env:"CHANNEL"`
+}
+
+func TestTypedErrors(t *testing.T) {
+	t.Parallel()
+	err := BindFrom(MapLookuper{"PORT": "eighty"}, &Config{})
+
+	var missing *MissingError
+	assert.True(t, errors.As(err, &missing))
+	assert.Equal(t, &MissingError{Field: "Config.Credentials.KeyID", Name: "CREDENTIALS_KEY_ID"}, missing)
+
+	var bindErr *BindError
+	assert.True(t, errors.As(err, &bindErr))
+	assert.Len(t, bindErr.Errors, 4)
+
+	var parse *ParseError
+	assert.True(t, errors.As(bindErr.Errors[1], &parse))
+	assert.Equal(t, "Config.Credentials.Timeout", parse.Field)
+	assert.Equal(t, "CREDENTIALS_TIMEOUT", parse.Name)
+	assert.Equal(t, "soon", parse.Value)
+	assert.True(t, parse.Default)
+	assert.Equal(t, reflect.TypeOf(time.Duration(0)), parse.Type)
+	assert.Error(t, parse.Unwrap())
+
+	assert.True(t, errors.As(bindErr.Errors[2], &parse))
+	assert.Equal(t, "Config.Port", parse.Field)
+	assert.Equal(t, "PORT", parse.Name)
+	assert.Equal(t, "eighty", parse.Value)
+	assert.False(t, parse.Default)
+	assert.EqualError(t, parse, "can't parse value 'eighty' of PORT to int: invalid syntax of int")
+
+	var unsupported *UnsupportedTypeError
+	assert.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, "Config.Channel", unsupported.Field)
+	assert.Equal(t, "CHANNEL", unsupported.Name)
+	assert.Equal(t, reflect.TypeOf(make(chan int)), unsupported.Type)
+
+	// cause of ParseError
+	errInvalid := errors.New("invalid rgb")
+	decoder := WithDecoder(reflect.TypeOf(rgb{}), func(string) (interface{}, error) { return nil, errInvalid })
+	err = BindWithOptions(&struct {
+		Colors map[string]rgb `env:"COLORS"`
+	}{}, WithLookuper(MapLookuper{"COLORS": "a:red"}), decoder)
+	assert.True(t, errors.Is(err, errInvalid))
+	assert.True(t, errors.As(err, &parse))
+	assert.Equal(t, "Colors", parse.Field)
+	assert.Contains(t, parse.Error(), "key 'a'")
+
+	// invalid boolean names variable and value in the right order
+	err = BindFrom(MapLookuper{"ENABLED": "maybe"}, &struct {
+		Enabled bool `env:"ENABLED"`
+	}{})
+	assert.True(t, errors.As(err, &parse))
+	assert.Equal(t, "ENABLED", parse.Name)
+	assert.Equal(t, "maybe", parse.Value)
+}
+
+func TestDeclarationOrder(t *testing.T) {
+	t.Parallel()
+	type traced string
+	type Leaf struct {
+		First  traced `env:"FIRST"`
+		Second traced `env:"SECOND"`
+	}
+	type token struct {
+		A      traced `env:"A"`
+		Nested Leaf   `env:"NESTED"`
+		B      traced `env:"B"`
+		Ptr    *Leaf  `env:"PTR"`
+		Slice  []Leaf `env:"SLICE"`
+		C      traced `env:"C"`
+	}
+	source := MapLookuper{
+		"A": "A", "B": "B", "C": "C",
+		"NESTED_FIRST": "NESTED_FIRST", "NESTED_SECOND": "NESTED_SECOND",
+		"PTR_FIRST": "PTR_FIRST", "PTR_SECOND": "PTR_SECOND",
+		"SLICE_0_FIRST": "SLICE_0_FIRST", "SLICE_0_SECOND": "SLICE_0_SECOND",
+		"SLICE_1_FIRST": "SLICE_1_FIRST", "SLICE_1_SECOND": "SLICE_1_SECOND",
+	}
+	expected := []string{"A", "NESTED_FIRST", "NESTED_SECOND", "B", "PTR_FIRST", "PTR_SECOND",
+		"SLICE_0_FIRST", "SLICE_0_SECOND", "SLICE_1_FIRST", "SLICE_1_SECOND", "C"}
+	for i := 0; i < 10; i++ {
+		var order []string
+		decoder := WithDecoder(reflect.TypeOf(traced("")), func(s string) (interface{}, error) {
+			order = append(order, s)
+			return traced(s), nil
+		})
+		err := BindWithOptions(&token{}, WithLookuper(source), decoder)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, order)
+	}
+}
+
+type tlsConfig struct {
+	Cert string `env:"CERT"`
+	Key  string `env:"KEY"`
+}
+
+func (c *tlsConfig) Validate() error {
+	if c.Cert != "" && c.Key == "" {
+		return fmt.Errorf("TLS_CERT requires TLS_KEY")
+	}
+	return nil
+}
+
+type poolConfig struct {
+	Min     int `env:"MIN"`
+	Max     int `env:"MAX"`
+	Workers int `env:"WORKERS, default=2"`
+	calls   *[]string
+}
+
+func (c *poolConfig) SetDefaults() {
+	c.Max = 16
+	c.Workers = 8
+}
+
+func (c poolConfig) Validate() error {
+	*c.calls = append(*c.calls, "pool")
+	if c.Min > c.Max {
+		return fmt.Errorf("MIN %d is greater than MAX %d", c.Min, c.Max)
+	}
+	return nil
+}
+
+type server struct {
+	Name  string     `env:"NAME, require=true"`
+	TLS   tlsConfig  `env:"TLS"`
+	Pool  poolConfig `env:"POOL"`
+	calls []string
+}
+
+func (s *server) Validate() error {
+	s.calls = append(s.calls, "server")
+	if s.Name == "invalid" {
+		return fmt.Errorf("invalid name")
+	}
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+	s := &server{}
+	s.Pool.calls = &s.calls
+	err := BindFrom(MapLookuper{"NAME": "orders", "POOL_MIN": "4"}, s)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, s.Pool.Min)
+	// computed default is kept, tag default takes precedence
+	assert.Equal(t, 16, s.Pool.Max)
+	assert.Equal(t, 2, s.Pool.Workers)
+	// nested structures are validated before their parents
+	assert.Equal(t, []string{"pool", "server"}, s.calls)
+
+	s = &server{}
+	s.Pool.calls = &s.calls
+	err = BindFrom(MapLookuper{"NAME": "invalid", "TLS_CERT": "cert.pem", "POOL_MIN": "32"}, s)
+	var bindErr *BindError
+	assert.True(t, errors.As(err, &bindErr))
+	assert.Len(t, bindErr.Errors, 3)
+	assert.EqualError(t, bindErr.Errors[0], "server.TLS: TLS_CERT requires TLS_KEY")
+	assert.EqualError(t, bindErr.Errors[1], "server.Pool: MIN 32 is greater than MAX 16")
+	assert.EqualError(t, bindErr.Errors[2], "server: invalid name")
+
+	// structures are not validated if binding fails
+	s = &server{}
+	s.Pool.calls = &s.calls
+	err = BindFrom(MapLookuper{"TLS_CERT": "cert.pem"}, s)
+	assert.EqualError(t, err, "NAME is required")
+	assert.Empty(t, s.calls)
+}
+
+func TestConditionalRequirements(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		TLSEnabled bool   `env:"TLS_ENABLED, default=false"`
+		TLSCert    string `env:"TLS_CERT, required_if=TLS_ENABLED:true"`
+		Mode       string `env:"MODE, default=prod"`
+		Debug      string `env:"DEBUG_TOKEN, required_unless=MODE:prod"`
+		Region     string `env:"REGION, required_if=CLOUD"`
+		DBURL      string `env:"DB_URL, exclusive=db"`
+		DBHost     string `env:"DB_HOST, exclusive=db"`
+		DBSocket   string `env:"DB_SOCKET, exclusive=db"`
+	}
+	err := BindFrom(MapLookuper{"DB_URL": "postgres://db"}, &token{})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		source  MapLookuper
+		message string
+	}{
+		{"required if", MapLookuper{"TLS_ENABLED": "1"},
+			"TLS_CERT is required by required_if=TLS_ENABLED:true"},
+		{"required unless", MapLookuper{"MODE": "dev"},
+			"DEBUG_TOKEN is required by required_unless=MODE:prod"},
+		{"required if variable outside of structure is set", MapLookuper{"CLOUD": "aws"},
+			"REGION is required by required_if=CLOUD"},
+		{"exclusive", MapLookuper{"DB_URL": "postgres://db", "DB_SOCKET": "/run/db.sock"},
+			"only one of DB_URL, DB_SOCKET can be set (exclusive=db)"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			err := BindFrom(test.source, &token{})
+			assert.EqualError(t, err, test.message)
+		})
+	}
+
+	var missing *MissingError
+	err = BindFrom(MapLookuper{"TLS_ENABLED": "true"}, &token{})
+	assert.True(t, errors.As(err, &missing))
+	assert.Equal(t, &MissingError{Field: "token.TLSCert", Name: "TLS_CERT", Condition: "required_if=TLS_ENABLED:true"},
+		missing)
+
+	var exclusive *ExclusiveError
+	err = BindFrom(MapLookuper{"DB_URL": "postgres://db", "DB_HOST": "db", "DB_SOCKET": "/run/db.sock"}, &token{})
+	assert.True(t, errors.As(err, &exclusive))
+	assert.Equal(t, &ExclusiveError{Group: "db", Fields: []string{"token.DBURL", "token.DBHost", "token.DBSocket"},
+		Names: []string{"DB_URL", "DB_HOST", "DB_SOCKET"}}, exclusive)
+
+	type invalid struct {
+		Cert string `env:"CERT, required_unless=:true"`
+	}
+	err = BindFrom(MapLookuper{}, &invalid{})
+	assert.EqualError(t, err, "invalid tag of invalid.Cert at column 7: invalid required_unless=:true")
+}
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+	type Log struct {
+		Level  string `env:"APP_LOG_LEVEL|LOG_LEVEL, default=info"`
+		Format string `env:"FORMAT|FMT, require=true"`
+	}
+	type token struct {
+		Log  Log    `env:"LOG"`
+		Name string `env:"NAME | SERVICE_NAME | APP"`
+		Port int    `env:"PORT|HTTP_PORT, default=8080"`
+	}
+	tests := []struct {
+		name     string
+		source   MapLookuper
+		expected token
+		sources  map[string]string
+		warnings []Warning
+	}{
+		{"primary names", MapLookuper{"LOG_APP_LOG_LEVEL": "debug", "LOG_FORMAT": "json", "NAME": "orders",
+			"SERVICE_NAME": "legacy", "PORT": "80", "HTTP_PORT": "81"},
+			token{Log: Log{Level: "debug", Format: "json"}, Name: "orders", Port: 80},
+			map[string]string{"token.Log.Level": "LOG_APP_LOG_LEVEL", "token.Log.Format": "LOG_FORMAT",
+				"token.Name": "NAME", "token.Port": "PORT"},
+			nil},
+		{"aliases", MapLookuper{"LOG_LOG_LEVEL": "warn", "LOG_FMT": "text", "APP": "orders"},
+			token{Log: Log{Level: "warn", Format: "text"}, Name: "orders", Port: 8080},
+			map[string]string{"token.Log.Level": "LOG_LOG_LEVEL", "token.Log.Format": "LOG_FMT", "token.Name": "APP"},
+			[]Warning{
+				{Field: "token.Log.Level", Name: "LOG_LOG_LEVEL", Message: "LOG_LOG_LEVEL is deprecated, use LOG_APP_LOG_LEVEL"},
+				{Field: "token.Log.Format", Name: "LOG_FMT", Message: "LOG_FMT is deprecated, use LOG_FORMAT"},
+				{Field: "token.Name", Name: "APP", Message: "APP is deprecated, use NAME"},
+			}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var warnings []Warning
+			sources := map[string]string{}
+			tok := &token{}
+			err := BindWithOptions(tok, WithLookuper(test.source), WithSources(sources),
+				WithWarnings(func(w Warning) { warnings = append(warnings, w) }))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, *tok)
+			assert.Equal(t, test.sources, sources)
+			assert.Equal(t, test.warnings, warnings)
+		})
+	}
+
+	// the primary name is reported if none of aliases exists
+	err := BindFrom(MapLookuper{}, &token{})
+	assert.EqualError(t, err, "LOG_FORMAT is required")
+}
+
+func TestDeprecated(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		Foo     string `env:"FOO, deprecated=\"use FOO_V2\""`
+		FooV2   string `env:"FOO_V2"`
+		Legacy  string `env:"LEGACY, deprecated=\"\""`
+		Unused  string `env:"UNUSED, deprecated=use NEW, default=x"`
+		Renamed string `env:"NEW_NAME|OLD_NAME"`
+	}
+	source := MapLookuper{"FOO": "foo", "LEGACY": "legacy", "OLD_NAME": "old"}
+	var warnings []Warning
+	tok := &token{}
+	err := BindWithOptions(tok, WithLookuper(source), WithWarnings(func(w Warning) { warnings = append(warnings, w) }))
+	assert.NoError(t, err)
+	assert.Equal(t, token{Foo: "foo", Legacy: "legacy", Unused: "x", Renamed: "old"}, *tok)
+	assert.Equal(t, []Warning{
+		{Field: "token.Foo", Name: "FOO", Message: "FOO is deprecated: use FOO_V2"},
+		{Field: "token.Legacy", Name: "LEGACY", Message: "LEGACY is deprecated"},
+		{Field: "token.Renamed", Name: "OLD_NAME", Message: "OLD_NAME is deprecated, use NEW_NAME"},
+	}, warnings)
+
+	// without handler warnings are ignored
+	err = BindFrom(source, &token{})
+	assert.NoError(t, err)
+
+	// strict mode
+	var bindErr *BindError
+	var deprecated *DeprecatedError
+	warnings = nil
+	err = BindWithOptions(&token{}, WithLookuper(source), Strict(),
+		WithWarnings(func(w Warning) { warnings = append(warnings, w) }))
+	assert.True(t, errors.As(err, &bindErr))
+	assert.Len(t, bindErr.Errors, 3)
+	assert.True(t, errors.As(err, &deprecated))
+	assert.Equal(t, &DeprecatedError{Field: "token.Foo", Name: "FOO", Message: "FOO is deprecated: use FOO_V2"}, deprecated)
+	assert.EqualError(t, err, "FOO is deprecated: use FOO_V2; LEGACY is deprecated; OLD_NAME is deprecated, use NEW_NAME")
+	assert.Empty(t, warnings)
+}
+
+func TestSnakeCase(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"Port":         "PORT",
+		"MaxIdleConns": "MAX_IDLE_CONNS",
+		"HTTPPort":     "HTTP_PORT",
+		"ID":           "ID",
+		"UserID":       "USER_ID",
+		"APIKeyID":     "API_KEY_ID",
+		"Base64Data":   "BASE64_DATA",
+		"S3Bucket":     "S3_BUCKET",
+		"TLS":          "TLS",
+	}
+	for name, expected := range tests {
+		assert.Equal(t, expected, snakeCase(name), name)
+	}
+}
+
+func TestAutoNames(t *testing.T) {
+	t.Parallel()
+	type Database struct {
+		MaxIdleConns int    `env:", default=2"`
+		URL          string `env:"DSN"`
+	}
+	type Embedded struct {
+		Region string
+	}
+	type token struct {
+		Embedded
+		HTTPPort int
+		Name     string `env:"SERVICE_NAME"`
+		Skipped  string `env:"-"`
+		Database Database
+		Backups  []Database
+		private  string
+	}
+	source := MapLookuper{
+		"HTTP_PORT":               "8080",
+		"SERVICE_NAME":            "orders",
+		"NAME":                    "ignored",
+		"SKIPPED":                 "ignored",
+		"REGION":                  "eu",
+		"DATABASE_MAX_IDLE_CONNS": "10",
+		"DATABASE_DSN":            "postgres://db",
+		"BACKUPS_0_DSN":           "postgres://backup",
+		"PRIVATE":                 "ignored",
+	}
+	tok := &token{}
+	err := BindWithOptions(tok, WithLookuper(source), AutoNames())
+	assert.NoError(t, err)
+	assert.Equal(t, token{
+		Embedded: Embedded{Region: "eu"},
+		HTTPPort: 8080,
+		Name:     "orders",
+		Database: Database{MaxIdleConns: 10, URL: "postgres://db"},
+		Backups:  []Database{{MaxIdleConns: 2, URL: "postgres://backup"}},
+	}, *tok)
+
+	// untagged fields are skipped without AutoNames
+	tok = &token{}
+	err = BindFrom(source, tok)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tok.HTTPPort)
+	assert.Equal(t, "orders", tok.Name)
+}
+
+func TestBindWithPrefix(t *testing.T) {
+	t.Parallel()
+	type Endpoint struct {
+		URL   string `env:"URL"`
+		Trace bool   `env:"TRACE, noprefix=true"`
+	}
+	type token struct {
+		Port      int        `env:"PORT, require=true"`
+		LogLevel  string     `env:"LOG_LEVEL, noprefix=true"`
+		Primary   Endpoint   `env:"PRIMARY"`
+		Shared    Endpoint   `env:"SHARED, noprefix=true"`
+		Upstreams []Endpoint `env:"UPSTREAM"`
+		Common    []Endpoint `env:"COMMON, noprefix=true"`
+	}
+	source := MapLookuper{
+		"ORDERS_PORT":           "8080",
+		"BILLING_PORT":          "9090",
+		"PORT":                  "80",
+		"LOG_LEVEL":             "debug",
+		"ORDERS_PRIMARY_URL":    "https://orders",
+		"PRIMARY_TRACE":         "true",
+		"SHARED_TRACE":          "true",
+		"SHARED_URL":            "https://shared",
+		"ORDERS_UPSTREAM_0_URL": "https://upstream",
+		"COMMON_0_URL":          "https://common",
+		"UPSTREAM_0_TRACE":      "true",
+	}
+	// noprefix drops the global prefix only, prefixes of nested structures are kept
+	tok := &token{}
+	err := BindWithPrefix(tok, "ORDERS", WithLookuper(source))
+	assert.NoError(t, err)
+	assert.Equal(t, token{
+		Port:      8080,
+		LogLevel:  "debug",
+		Primary:   Endpoint{URL: "https://orders", Trace: true},
+		Shared:    Endpoint{URL: "https://shared", Trace: true},
+		Upstreams: []Endpoint{{URL: "https://upstream", Trace: true}},
+		Common:    []Endpoint{{URL: "https://common"}},
+	}, *tok)
+
+	tok = &token{}
+	err = BindWithOptions(tok, WithLookuper(source), WithPrefix("BILLING"))
+	assert.NoError(t, err)
+	assert.Equal(t, 9090, tok.Port)
+
+	err = BindWithPrefix(&token{}, "INVENTORY", WithLookuper(source))
+	assert.EqualError(t, err, "INVENTORY_PORT is required")
+}
+
+func TestNestedPrefixes(t *testing.T) {
+	t.Parallel()
+	type Endpoint struct {
+		URL string `env:"URL"`
+	}
+	type Base struct {
+		Region string `env:"REGION"`
+	}
+	type Database struct {
+		Primary   Endpoint `env:"PRIMARY"`
+		Replaced  Endpoint `env:"REPLICA, prefix=replace"`
+		Ignored   Endpoint `env:"IGNORED, prefix=ignore"`
+		Inherited Endpoint `env:"INHERITED, prefix=inherit"`
+		Untagged  Endpoint
+		Backups   []Endpoint `env:"BACKUP"`
+	}
+	type token struct {
+		Base
+		Tagged   Base     `env:"TAGGED"`
+		Database Database `env:"DB"`
+	}
+	tests := []struct {
+		name    string
+		options []Option
+		source  MapLookuper
+	}{
+		{"default separator", nil, MapLookuper{
+			"REGION":           "eu",
+			"TAGGED_REGION":    "us",
+			"DB_PRIMARY_URL":   "primary",
+			"REPLICA_URL":      "replica",
+			"URL":              "ignored",
+			"DB_INHERITED_URL": "inherited",
+			"DB_BACKUP_0_URL":  "backup",
+		}},
+		{"double underscore", []Option{WithSeparator("__")}, MapLookuper{
+			"REGION":             "eu",
+			"TAGGED__REGION":     "us",
+			"DB__PRIMARY__URL":   "primary",
+			"REPLICA__URL":       "replica",
+			"URL":                "ignored",
+			"DB__INHERITED__URL": "inherited",
+			"DB__BACKUP__0__URL": "backup",
+		}},
+		{"global prefix", []Option{WithSeparator("__"), WithPrefix("APP__")}, MapLookuper{
+			"APP__REGION":             "eu",
+			"APP__TAGGED__REGION":     "us",
+			"APP__DB__PRIMARY__URL":   "primary",
+			"APP__REPLICA__URL":       "replica",
+			"APP__URL":                "ignored",
+			"APP__DB__INHERITED__URL": "inherited",
+			"APP__DB__BACKUP__0__URL": "backup",
+		}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			tok := &token{}
+			err := BindWithOptions(tok, append(test.options, WithLookuper(test.source))...)
+			assert.NoError(t, err)
+			assert.Equal(t, token{
+				Base:   Base{Region: "eu"},
+				Tagged: Base{Region: "us"},
+				Database: Database{
+					Primary:   Endpoint{URL: "primary"},
+					Replaced:  Endpoint{URL: "replica"},
+					Ignored:   Endpoint{URL: "ignored"},
+					Inherited: Endpoint{URL: "inherited"},
+					Untagged:  Endpoint{URL: ""},
+					Backups:   []Endpoint{{URL: "backup"}},
+				},
+			}, *tok)
+		})
+	}
+
+	type invalid struct {
+		Endpoint Endpoint `env:"ENDPOINT, prefix=parent"`
+	}
+	err := BindFrom(MapLookuper{}, &invalid{})
+	assert.EqualError(t, err, "invalid tag of invalid.Endpoint at column 11: invalid prefix=parent, expected inherit, "+
+		"replace or ignore")
+	var tagErr *TagError
+	assert.True(t, errors.As(err, &tagErr))
+	assert.Equal(t, "ENDPOINT, prefix=parent", tagErr.Tag)
+}
+
+func TestGetter(t *testing.T) {
+	t.Parallel()
+	g := NewGetter(MapLookuper{"INT": "1", "FLOAT": "1.5", "BOOL": "true", "DURATION": "1m", "STRINGS": "us, fr",
+		"INTS": "1,2", "FLOATS": "1.5", "BOOLS": "true,false", "DURATIONS": "1s,1m", "INVALID": "x"})
+
+	assert.Equal(t, "1", g.GetEnvAsStringOrFallback("INT", "2"))
+	assert.Equal(t, "2", g.GetEnvAsStringOrFallback("NONE", "2"))
+	assert.Equal(t, []string{"us", "fr"}, g.GetEnvAsArrayOfStringsOrFallback("STRINGS", nil))
+
+	i, err := g.GetEnvAsIntOrFallback("INT", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	_, err = g.GetEnvAsIntOrFallback("INVALID", 2)
+	assert.Error(t, err)
+	is, err := g.GetEnvAsArrayOfIntsOrFallback("INTS", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, is)
+
+	f, err := g.GetEnvAsFloat64OrFallback("FLOAT", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+	fs, err := g.GetEnvAsArrayOfFloat64OrFallback("FLOATS", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{1.5}, fs)
+
+	b, err := g.GetEnvAsBoolOrFallback("BOOL", false)
+	assert.NoError(t, err)
+	assert.True(t, b)
+	bs, err := g.GetEnvAsArrayOfBoolOrFallback("BOOLS", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, bs)
+
+	d, err := g.GetEnvAsDurationOrFallback("DURATION", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, d)
+	ds, err := g.GetEnvAsArrayOfDurationsOrFallback("DURATIONS", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, time.Minute}, ds)
+	d, err = g.GetEnvAsDurationOrFallback("NONE", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, d)
+}
+
 func TestPublicAPI(t *testing.T) {
 	defer cleanup()
 	_ = os.Setenv(envInt, "1")
@@ -1389,10 +2720,13 @@ func TestPublicAPI(t *testing.T) {
 	var ss []string
 	var is []int
 
+	var ds []time.Duration
+
 	var f float64
 	var b bool
 	var i int
 	var s string
+	var d time.Duration
 
 	fs, err = GetEnvAsArrayOfFloat64OrFallback(envFloat64Slice, []float64{20, 20})
 	assert.NoError(t, err)
@@ -1441,6 +2775,22 @@ func TestPublicAPI(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1., f)
 
+	_ = os.Setenv(envInt, "1s")
+	d, err = GetEnvAsDurationOrFallback(envInt, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, d)
+	d, err = GetEnvAsDurationOrFallback(none, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, d)
+
+	_ = os.Setenv(envIntSlice, "1s,2s")
+	ds, err = GetEnvAsArrayOfDurationsOrFallback(envIntSlice, []time.Duration{time.Minute})
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, ds)
+	ds, err = GetEnvAsArrayOfDurationsOrFallback(none, []time.Duration{time.Minute})
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Minute}, ds)
+
 	s = GetEnvAsStringOrFallback(envString, "1")
 	assert.Equal(t, "1", s)
 	s = GetEnvAsStringOrFallback(none, "1")
@@ -1532,6 +2882,12 @@ func setEnv(m map[string]string) {
 	}
 }
 
+func unsetEnv(keys ...string) {
+	for _, k := range keys {
+		_ = os.Unsetenv(k)
+	}
+}
+
 const (
 	tokenID          = "TOKEN_ID"
 	tokenValue       = "TOKEN_VALUE"
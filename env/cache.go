@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// structField contains metadata of the structure field which don't change between binds; i.e. the name and
+// parsed env tag
+type structField struct {
+	name      string
+	typ       reflect.Type
+	public    bool
+	anonymous bool
+	// skip is true for fields tagged env:"-"
+	skip bool
+	// raw tag including name derived by AutoNames
+	raw string
+	tag structTag
+	err *TagError
+	// env is template of env of the field with options of the tag; name, value and source are resolved while
+	// binding
+	env      env
+	noprefix bool
+	file     strTag
+	expand   strTag
+	prefix   strTag
+}
+
+// cacheKey identifies cached metadata. Tags depend on AutoNames option, the other options are applied
+// while binding
+type cacheKey struct {
+	t         reflect.Type
+	autoNames bool
+}
+
+// cache contains []structField per cacheKey
+var cache sync.Map
+
+// sizes contains number of meta entries of the last bind per cacheKey of bound structure, so meta is allocated
+// at once by following binds
+var sizes sync.Map
+
+// metaSize returns expected number of meta entries of structure type t
+func metaSize(t reflect.Type, o *options) int {
+	if size, found := sizes.Load(cacheKey{t: t, autoNames: o.autoNames}); found {
+		return size.(int)
+	}
+	return t.NumField()
+}
+
+// structFields returns metadata of fields of structure type t. Metadata are built once per type and cached
+func structFields(t reflect.Type, o *options) []structField {
+	const tagEnv = "env"
+	key := cacheKey{t: t, autoNames: o.autoNames}
+	if fields, found := cache.Load(key); found {
+		return fields.([]structField)
+	}
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tf := t.Field(i)
+		f := structField{name: tf.Name, typ: tf.Type, public: tf.PkgPath == "", anonymous: tf.Anonymous,
+			raw: tf.Tag.Get(tagEnv)}
+		if f.raw == "-" {
+			f.skip = true
+			fields = append(fields, f)
+			continue
+		}
+		if o.autoNames && f.public && !f.anonymous {
+			f.raw = autoName(f.raw, tf.Name)
+		}
+		var err error
+		if f.tag, err = parseStructTag("", f.raw); err != nil {
+			errors.As(err, &f.err)
+		}
+		f.options()
+		fields = append(fields, f)
+	}
+	actual, _ := cache.LoadOrStore(key, fields)
+	return actual.([]structField)
+}
+
+// options fills env template and options of the field from parsed tag, so they are not looked up on every bind
+func (f *structField) options() {
+	t := f.tag
+	f.env = env{
+		tag:            f.raw,
+		tagName:        t.name(),
+		req:            t.option("require"),
+		def:            t.option("default"),
+		protected:      t.option("protected"),
+		separator:      t.option("separator"),
+		kvSeparator:    t.option("kvseparator"),
+		min:            t.option("min"),
+		max:            t.option("max"),
+		oneof:          t.option("oneof"),
+		pattern:        t.option("pattern"),
+		requiredIf:     t.option("required_if"),
+		requiredUnless: t.option("required_unless"),
+		exclusive:      t.option("exclusive"),
+		deprecated:     t.option("deprecated"),
+	}
+	f.noprefix = t.option("noprefix").isTrue()
+	f.file, f.expand, f.prefix = t.option("file"), t.option("expand"), t.option("prefix")
+}
+
+// tagError returns copy of tag error of the field with path of the field
+func (f structField) tagError(path string) error {
+	err := *f.err
+	err.Field = path
+	return &err
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type benchmarkEndpoint struct {
+	URL     string        `env:"URL, require=true"`
+	Timeout time.Duration `env:"TIMEOUT, default=5s, min=1s"`
+	Retries int           `env:"RETRIES, default=3, max=10"`
+}
+
+type benchmarkConfig struct {
+	Name      string              `env:"NAME, require=true, pattern=^[a-z]+$"`
+	Port      int                 `env:"PORT, default=8080, min=1, max=65535"`
+	LogLevel  string              `env:"APP_LOG_LEVEL|LOG_LEVEL, default=info, oneof=[debug|info|warn]"`
+	Regions   []string            `env:"REGIONS, default=[eu,us]"`
+	Labels    map[string]string   `env:"LABELS, default={team:core,tier:gold}"`
+	Enabled   bool                `env:"ENABLED, default=true"`
+	Ratio     float64             `env:"RATIO, default=0.5"`
+	Primary   benchmarkEndpoint   `env:"PRIMARY"`
+	Failover  *benchmarkEndpoint  `env:"FAILOVER"`
+	Upstreams []benchmarkEndpoint `env:"UPSTREAM"`
+}
+
+var benchmarkSource = MapLookuper{
+	"NAME":               "orders",
+	"PORT":               "9000",
+	"LOG_LEVEL":          "debug",
+	"REGIONS":            "eu,us,au",
+	"PRIMARY_URL":        "https://primary",
+	"FAILOVER_URL":       "https://failover",
+	"UPSTREAM_0_URL":     "https://upstream0",
+	"UPSTREAM_1_URL":     "https://upstream1",
+	"UPSTREAM_1_RETRIES": "5",
+}
+
+func TestStructFieldsCache(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		Port    int    `env:"PORT, default=80"`
+		Skipped string `env:"-"`
+		Name    string
+		Invalid string `env:"INVALID, requried=true"`
+	}
+	typ := reflect.TypeOf(token{})
+	fields := structFields(typ, newOptions())
+	assert.Len(t, fields, 4)
+	assert.Equal(t, "PORT", fields[0].tag.name())
+	assert.True(t, fields[1].skip)
+	assert.Equal(t, "", fields[2].raw)
+	assert.NotNil(t, fields[3].err)
+	// the same slice is returned for the same type
+	assert.Equal(t, reflect.ValueOf(fields).Pointer(), reflect.ValueOf(structFields(typ, newOptions())).Pointer())
+	// AutoNames changes tags, so the metadata are cached separately
+	auto := structFields(typ, newOptions(AutoNames()))
+	assert.Equal(t, "NAME", auto[2].tag.name())
+	assert.Equal(t, "", fields[2].tag.name())
+
+	// path of the field is not cached within tag error
+	type parent struct {
+		First  token `env:"FIRST"`
+		Second token `env:"SECOND"`
+	}
+	err := BindFrom(MapLookuper{}, &parent{})
+	assert.EqualError(t, err, "invalid tag of parent.First.Invalid at column 10: unknown option requried; "+
+		"invalid tag of parent.Second.Invalid at column 10: unknown option requried")
+}
+
+func TestConcurrentBind(t *testing.T) {
+	t.Parallel()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := &benchmarkConfig{}
+			assert.NoError(t, BindFrom(benchmarkSource, c))
+			assert.Equal(t, "orders", c.Name)
+			assert.Len(t, c.Upstreams, 2)
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkBind(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := BindFrom(benchmarkSource, &benchmarkConfig{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBindUncached drops cached metadata, sizes, patterns and unmarshalers before every bind, which
+// corresponds to binding without cache
+func BenchmarkBindUncached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, c := range []*sync.Map{&cache, &sizes, &patterns, &unmarshalers} {
+			c.Range(func(key, _ interface{}) bool {
+				c.Delete(key)
+				return true
+			})
+		}
+		if err := BindFrom(benchmarkSource, &benchmarkConfig{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// conditions evaluates required_if, required_unless and exclusive rules against resolved values of variables,
+// i.e. env values or defaults
+type conditions struct {
+	values   map[string]string
+	groups   map[string][]field
+	lookuper Lookuper
+}
+
+func newConditions(m meta, o *options) *conditions {
+	c := &conditions{values: map[string]string{}, groups: map[string][]field{}, lookuper: o.lookuper}
+	for _, v := range m {
+		if v.validator || v.err != nil {
+			continue
+		}
+		if _, found := c.values[v.env.name]; !found {
+			switch {
+			case v.env.present:
+				c.values[v.env.name] = v.env.value
+			case v.env.def.exists:
+				c.values[v.env.name] = v.env.def.value
+			}
+		}
+		if _, found := c.values[v.env.source]; !found && v.env.present {
+			c.values[v.env.source] = v.env.value
+		}
+		if v.env.exclusive.exists && v.env.present {
+			group := strings.TrimSpace(v.env.exclusive.value)
+			c.groups[group] = append(c.groups[group], v)
+		}
+	}
+	return c
+}
+
+// required returns MissingError if env variable of the field doesn't exist and required_if condition is met or
+// required_unless condition is not met
+func (c *conditions) required(v field) (err error) {
+	if v.env.present {
+		return
+	}
+	if v.env.requiredIf.exists {
+		if err = c.check(v, "required_if", v.env.requiredIf); err != nil {
+			return
+		}
+	}
+	if v.env.requiredUnless.exists {
+		err = c.check(v, "required_unless", v.env.requiredUnless)
+	}
+	return
+}
+
+// check evaluates condition VAR:value, or VAR which is met if VAR is set to non-empty value. Returns MissingError
+// if the field is required by the rule
+func (c *conditions) check(v field, rule string, param strTag) (err error) {
+	var met bool
+	condition := param.value
+	parts := strings.SplitN(condition, ":", 2)
+	name := strings.TrimSpace(parts[0])
+	if name == "" {
+		return v.tagError(param, "invalid %s=%s", rule, condition)
+	}
+	value, found := c.values[name]
+	if !found {
+		value, found = c.lookuper.LookupEnv(name)
+	}
+	if len(parts) == 2 {
+		met = found && equal(value, strings.TrimSpace(parts[1]))
+	} else {
+		met = found && value != ""
+	}
+	if met == (rule == "required_if") {
+		err = &MissingError{Field: v.path, Name: v.env.name, Condition: fmt.Sprintf("%s=%s", rule, condition)}
+	}
+	return
+}
+
+// exclusive returns ExclusiveError if v is the last of several fields of exclusive group, which are set
+func (c *conditions) exclusive(v field) error {
+	if !v.env.exclusive.exists || !v.env.present {
+		return nil
+	}
+	group := strings.TrimSpace(v.env.exclusive.value)
+	fields := c.groups[group]
+	if len(fields) < 2 || fields[len(fields)-1].path != v.path {
+		return nil
+	}
+	e := &ExclusiveError{Group: group}
+	for _, f := range fields {
+		e.Fields = append(e.Fields, f.path)
+		e.Names = append(e.Names, f.env.name)
+	}
+	return e
+}
+
+// equal compares values as booleans if both of them are booleans, e.g. "1" and "true", otherwise as strings
+func equal(a, b string) bool {
+	x, errA := strconv.ParseBool(a)
+	y, errB := strconv.ParseBool(b)
+	if errA == nil && errB == nil {
+		return x == y
+	}
+	return a == b
+}
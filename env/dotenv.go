@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadDotenv reads dotenv files and returns their variables as MapLookuper, which can be passed to BindFrom.
+// Files are parsed in given order, so variables in later files override variables in earlier files. The process
+// environment is not modified
+func LoadDotenv(filenames ...string) (m MapLookuper, err error) {
+	m = MapLookuper{}
+	for _, filename := range filenames {
+		var f *os.File
+		var dm MapLookuper
+		// #nosec G304; reading files provided by caller is the purpose of the function
+		f, err = os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		dm, err = ParseDotenv(f, filename)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range dm {
+			m[k] = v
+		}
+	}
+	return m, nil
+}
+
+// ParseDotenv parses dotenv content from reader r. The filename is used in error messages only.
+// The parser supports comments, export prefixes, single quoted (literal) values, double quoted values with escape
+// sequences (\n, \r, \t, \", \\, \$, escaped line break) and quoted values spanning multiple lines; e.g.
+//
+//	# comment
+//	export NAME=orders # inline comment
+//	SECRET='p@$$word'
+//	CERT="-----BEGIN CERTIFICATE-----
+//	MIIB...
+//	-----END CERTIFICATE-----"
+func ParseDotenv(r io.Reader, filename string) (m MapLookuper, err error) {
+	var b []byte
+	b, err = io.ReadAll(r)
+	if err != nil {
+		return
+	}
+	p := &dotenvParser{filename: filename, src: []rune(string(b)), line: 1}
+	m = MapLookuper{}
+	for {
+		var k, v string
+		var ok bool
+		k, v, ok, err = p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return m, nil
+		}
+		m[k] = v
+	}
+}
+
+type dotenvParser struct {
+	filename string
+	src      []rune
+	pos      int
+	line     int
+}
+
+// next parses next key-value pair. Returns false if the end of content was reached
+func (p *dotenvParser) next() (k, v string, ok bool, err error) {
+	p.skipEmptyLines()
+	if p.eof() {
+		return
+	}
+	k = p.key()
+	if k == "export" && p.isBlank() {
+		p.skipBlanks()
+		k = p.key()
+	}
+	if k == "" {
+		return "", "", false, p.errorf("invalid character '%c', expecting variable name", p.peek())
+	}
+	p.skipBlanks()
+	if p.peek() != '=' {
+		return "", "", false, p.errorf("missing '=' after %s", k)
+	}
+	p.pos++
+	p.skipBlanks()
+	switch p.peek() {
+	case '\'':
+		v, err = p.quoted('\'')
+	case '"':
+		v, err = p.quoted('"')
+	default:
+		v = p.unquoted()
+	}
+	if err != nil {
+		return
+	}
+	return k, v, true, p.endOfLine()
+}
+
+// key reads variable name
+func (p *dotenvParser) key() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c != '_' && c != '.' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9' && p.pos > start) {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// quoted reads value enclosed in quotes q. Value may span multiple lines. Escape sequences are
+// processed within double quotes only
+func (p *dotenvParser) quoted(q rune) (v string, err error) {
+	var sb strings.Builder
+	line := p.line
+	p.pos++
+	for !p.eof() {
+		c := p.src[p.pos]
+		p.pos++
+		switch {
+		case c == q:
+			return sb.String(), nil
+		case c == '\n':
+			p.line++
+		case c == '\\' && q == '"' && !p.eof():
+			c = p.src[p.pos]
+			p.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case '"', '\\', '$':
+			case '\n':
+				// escaped line break joins lines
+				p.line++
+				continue
+			default:
+				sb.WriteRune('\\')
+			}
+		}
+		sb.WriteRune(c)
+	}
+	return "", fmt.Errorf("%s:%d: unterminated quoted value", p.filename, line)
+}
+
+// unquoted reads value up to the end of line. Inline comment (" #") and surrounding whitespaces are removed
+func (p *dotenvParser) unquoted() string {
+	start := p.pos
+	for !p.eof() && p.peek() != '\n' {
+		if p.peek() == '#' && p.pos > start && isBlank(p.src[p.pos-1]) {
+			break
+		}
+		p.pos++
+	}
+	return strings.TrimSpace(string(p.src[start:p.pos]))
+}
+
+// endOfLine consumes optional comment and line break after the value
+func (p *dotenvParser) endOfLine() error {
+	p.skipBlanks()
+	if p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.pos++
+		}
+	}
+	if p.peek() == '\r' {
+		p.pos++
+	}
+	if p.eof() {
+		return nil
+	}
+	if p.peek() != '\n' {
+		return p.errorf("unexpected character '%c' after value", p.peek())
+	}
+	p.pos++
+	p.line++
+	return nil
+}
+
+// skipEmptyLines skips whitespaces, empty lines and comments
+func (p *dotenvParser) skipEmptyLines() {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case isBlank(c) || c == '\r':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *dotenvParser) skipBlanks() {
+	for p.isBlank() {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) isBlank() bool {
+	return !p.eof() && isBlank(p.peek())
+}
+
+func (p *dotenvParser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s:%d: %s", p.filename, p.line, fmt.Sprintf(format, args...))
+}
+
+func isBlank(c rune) bool {
+	return c == ' ' || c == '\t'
+}
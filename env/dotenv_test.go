@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dotenv = `# service configuration
+NAME=orders
+export PORT = 9000 # inline comment
+EMPTY=
+HASH=abc#def
+SINGLE='p@$$word # not a comment \n'
+DOUBLE="line1\nline2\t\"quoted\" \\ \$HOME"
+	INDENTED=value   
+CERT="-----BEGIN CERTIFICATE-----
+MIIB
+-----END CERTIFICATE-----"
+JOINED="first \
+second"
+MULTI_SINGLE='a
+b'
+
+export REGIONS=eu-west-1,us-east-1
+NAME=payments
+`
+
+func TestParseDotenv(t *testing.T) {
+	t.Parallel()
+	m, err := ParseDotenv(strings.NewReader(dotenv), ".env")
+	assert.NoError(t, err)
+	assert.Equal(t, MapLookuper{
+		"NAME":         "payments",
+		"PORT":         "9000",
+		"EMPTY":        "",
+		"HASH":         "abc#def",
+		"SINGLE":       `p@$$word # not a comment \n`,
+		"DOUBLE":       "line1\nline2\t\"quoted\" \\ $HOME",
+		"INDENTED":     "value",
+		"CERT":         "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----",
+		"JOINED":       "first second",
+		"MULTI_SINGLE": "a\nb",
+		"REGIONS":      "eu-west-1,us-east-1",
+	}, m)
+}
+
+func TestParseDotenvWindowsLineEndings(t *testing.T) {
+	t.Parallel()
+	m, err := ParseDotenv(strings.NewReader("A=1\r\nB=\"2\"\r\n"), ".env")
+	assert.NoError(t, err)
+	assert.Equal(t, MapLookuper{"A": "1", "B": "2"}, m)
+}
+
+func TestParseDotenvSyntaxError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		content string
+		message string
+	}{
+		{"A=1\nB\n", ".env:2: missing '=' after B"},
+		{"A=1\n\n=2\n", ".env:3: invalid character '=', expecting variable name"},
+		{"# comment\nA=\"1\nB=2\n", ".env:2: unterminated quoted value"},
+		{"A='1'x\n", ".env:1: unexpected character 'x' after value"},
+		{"A=\"1\n2\"\n-B=1", ".env:3: invalid character '-', expecting variable name"},
+	}
+	for _, test := range tests {
+		_, err := ParseDotenv(strings.NewReader(test.content), ".env")
+		assert.EqualError(t, err, test.message)
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".env")
+	local := filepath.Join(dir, ".env.local")
+	assert.NoError(t, os.WriteFile(base, []byte("NAME=orders\nPORT=8080\n"), 0600))
+	assert.NoError(t, os.WriteFile(local, []byte("PORT=9000\n"), 0600))
+	type config struct {
+		Name string `env:"NAME, require=true"`
+		Port int    `env:"PORT"`
+	}
+
+	m, err := LoadDotenv(base, local)
+	assert.NoError(t, err)
+	c := &config{}
+	err = BindFrom(m, c)
+	assert.NoError(t, err)
+	assert.Equal(t, "orders", c.Name)
+	assert.Equal(t, 9000, c.Port)
+	_, exists := os.LookupEnv("NAME")
+	assert.False(t, exists)
+
+	_, err = LoadDotenv(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+	assert.NoError(t, os.WriteFile(local, []byte("PORT\n"), 0600))
+	_, err = LoadDotenv(base, local)
+	assert.EqualError(t, err, local+":1: missing '=' after PORT")
+}
@@ -31,25 +31,100 @@ limitations under the License.
 package env
 
 import (
-	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// Getter reads single variables from lookuper; e.g.
+// NewGetter(MapLookuper{"PORT": "8080"}).GetEnvAsIntOrFallback("PORT", 80). GetEnvAs*OrFallback functions of the
+// package read the process environment by Getter with OSLookuper
+type Getter struct {
+	lookuper Lookuper
+}
+
+var osGetter = NewGetter(OSLookuper{})
+
+// NewGetter returns Getter reading variables from lookuper
+func NewGetter(l Lookuper) Getter {
+	return Getter{lookuper: l}
+}
+
 // GetEnvAsStringOrFallback returns the env variable for the given key
 // and falls back to the given defaultValue if not set
 func GetEnvAsStringOrFallback(key, defaultValue string) string {
-	if v, ex := os.LookupEnv(key); ex {
+	return osGetter.GetEnvAsStringOrFallback(key, defaultValue)
+}
+
+// GetEnvAsArrayOfStringsOrFallback returns the env variable for the given key
+// and falls back to the given defaultValue if not set
+// GetEnvAsArrayOfStringsOrFallback trims all whitespaces from input i.e. "us, fr, au" -> {"us","fr","au"}
+func GetEnvAsArrayOfStringsOrFallback(key string, defaultValue []string) []string {
+	return osGetter.GetEnvAsArrayOfStringsOrFallback(key, defaultValue)
+}
+
+// GetEnvAsArrayOfIntsOrFallback returns the env variable for the given key
+// and falls back to the given defaultValue if not set
+func GetEnvAsArrayOfIntsOrFallback(key string, defaultValue []int) (ints []int, err error) {
+	return osGetter.GetEnvAsArrayOfIntsOrFallback(key, defaultValue)
+}
+
+// GetEnvAsArrayOfFloat64OrFallback returns the env variable for the given key
+// and falls back to the given defaultValue if not set
+func GetEnvAsArrayOfFloat64OrFallback(key string, defaultValue []float64) (floats []float64, err error) {
+	return osGetter.GetEnvAsArrayOfFloat64OrFallback(key, defaultValue)
+}
+
+// GetEnvAsArrayOfBoolOrFallback returns the env variable for the given key
+// and falls back to the given defaultValue if not set
+func GetEnvAsArrayOfBoolOrFallback(key string, defaultValue []bool) (bools []bool, err error) {
+	return osGetter.GetEnvAsArrayOfBoolOrFallback(key, defaultValue)
+}
+
+// GetEnvAsArrayOfDurationsOrFallback returns the env variable for the given key
+// and falls back to the given defaultValue if not set
+func GetEnvAsArrayOfDurationsOrFallback(key string, defaultValue []time.Duration) (durations []time.Duration, err error) {
+	return osGetter.GetEnvAsArrayOfDurationsOrFallback(key, defaultValue)
+}
+
+// GetEnvAsIntOrFallback returns the env variable (parsed as integer) for
+// the given key and falls back to the given defaultValue if not set
+func GetEnvAsIntOrFallback(key string, defaultValue int) (int, error) {
+	return osGetter.GetEnvAsIntOrFallback(key, defaultValue)
+}
+
+// GetEnvAsFloat64OrFallback returns the env variable (parsed as float64) for
+// the given key and falls back to the given defaultValue if not set
+func GetEnvAsFloat64OrFallback(key string, defaultValue float64) (float64, error) {
+	return osGetter.GetEnvAsFloat64OrFallback(key, defaultValue)
+}
+
+// GetEnvAsDurationOrFallback returns the env variable (parsed by time.ParseDuration) for
+// the given key and falls back to the given defaultValue if not set
+func GetEnvAsDurationOrFallback(key string, defaultValue time.Duration) (time.Duration, error) {
+	return osGetter.GetEnvAsDurationOrFallback(key, defaultValue)
+}
+
+// GetEnvAsBoolOrFallback returns the env variable for the given key,
+// parses it as boolean and falls back to the given defaultValue if not set
+func GetEnvAsBoolOrFallback(key string, defaultValue bool) (val bool, err error) {
+	return osGetter.GetEnvAsBoolOrFallback(key, defaultValue)
+}
+
+// GetEnvAsStringOrFallback returns the variable for the given key
+// and falls back to the given defaultValue if not set
+func (g Getter) GetEnvAsStringOrFallback(key, defaultValue string) string {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		return v
 	}
 	return defaultValue
 }
 
-// GetEnvAsArrayOfStringsOrFallback returns the env variable for the given key
+// GetEnvAsArrayOfStringsOrFallback returns the variable for the given key
 // and falls back to the given defaultValue if not set
 // GetEnvAsArrayOfStringsOrFallback trims all whitespaces from input i.e. "us, fr, au" -> {"us","fr","au"}
-func GetEnvAsArrayOfStringsOrFallback(key string, defaultValue []string) []string {
-	if v, ex := os.LookupEnv(key); ex {
+func (g Getter) GetEnvAsArrayOfStringsOrFallback(key string, defaultValue []string) []string {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		if v == "" {
 			return []string{}
 		}
@@ -61,10 +136,10 @@ func GetEnvAsArrayOfStringsOrFallback(key string, defaultValue []string) []strin
 	return defaultValue
 }
 
-// GetEnvAsArrayOfIntsOrFallback returns the env variable for the given key
+// GetEnvAsArrayOfIntsOrFallback returns the variable for the given key
 // and falls back to the given defaultValue if not set
-func GetEnvAsArrayOfIntsOrFallback(key string, defaultValue []int) (ints []int, err error) {
-	if v, ex := os.LookupEnv(key); ex {
+func (g Getter) GetEnvAsArrayOfIntsOrFallback(key string, defaultValue []int) (ints []int, err error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		if v == "" {
 			return []int{}, nil
 		}
@@ -82,10 +157,10 @@ func GetEnvAsArrayOfIntsOrFallback(key string, defaultValue []int) (ints []int,
 	return defaultValue, nil
 }
 
-// GetEnvAsArrayOfFloat64OrFallback returns the env variable for the given key
+// GetEnvAsArrayOfFloat64OrFallback returns the variable for the given key
 // and falls back to the given defaultValue if not set
-func GetEnvAsArrayOfFloat64OrFallback(key string, defaultValue []float64) (floats []float64, err error) {
-	if v, ex := os.LookupEnv(key); ex {
+func (g Getter) GetEnvAsArrayOfFloat64OrFallback(key string, defaultValue []float64) (floats []float64, err error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		if v == "" {
 			return []float64{}, nil
 		}
@@ -103,10 +178,10 @@ func GetEnvAsArrayOfFloat64OrFallback(key string, defaultValue []float64) (float
 	return defaultValue, nil
 }
 
-// GetEnvAsArrayOfBoolOrFallback returns the env variable for the given key
+// GetEnvAsArrayOfBoolOrFallback returns the variable for the given key
 // and falls back to the given defaultValue if not set
-func GetEnvAsArrayOfBoolOrFallback(key string, defaultValue []bool) (bools []bool, err error) {
-	if v, ex := os.LookupEnv(key); ex {
+func (g Getter) GetEnvAsArrayOfBoolOrFallback(key string, defaultValue []bool) (bools []bool, err error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		if v == "" {
 			return []bool{}, nil
 		}
@@ -124,10 +199,31 @@ func GetEnvAsArrayOfBoolOrFallback(key string, defaultValue []bool) (bools []boo
 	return defaultValue, nil
 }
 
-// GetEnvAsIntOrFallback returns the env variable (parsed as integer) for
+// GetEnvAsArrayOfDurationsOrFallback returns the variable for the given key
+// and falls back to the given defaultValue if not set
+func (g Getter) GetEnvAsArrayOfDurationsOrFallback(key string, defaultValue []time.Duration) (durations []time.Duration, err error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
+		if v == "" {
+			return []time.Duration{}, nil
+		}
+		slice := strings.Split(strings.ReplaceAll(v, " ", ""), ",")
+		for _, s := range slice {
+			var d time.Duration
+			d, err = time.ParseDuration(s)
+			if err != nil {
+				return defaultValue, err
+			}
+			durations = append(durations, d)
+		}
+		return durations, nil
+	}
+	return defaultValue, nil
+}
+
+// GetEnvAsIntOrFallback returns the variable (parsed as integer) for
 // the given key and falls back to the given defaultValue if not set
-func GetEnvAsIntOrFallback(key string, defaultValue int) (int, error) {
-	if v, ex := os.LookupEnv(key); ex {
+func (g Getter) GetEnvAsIntOrFallback(key string, defaultValue int) (int, error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		value, err := strconv.Atoi(v)
 		if err != nil {
 			return defaultValue, err
@@ -137,10 +233,10 @@ func GetEnvAsIntOrFallback(key string, defaultValue int) (int, error) {
 	return defaultValue, nil
 }
 
-// GetEnvAsFloat64OrFallback returns the env variable (parsed as float64) for
+// GetEnvAsFloat64OrFallback returns the variable (parsed as float64) for
 // the given key and falls back to the given defaultValue if not set
-func GetEnvAsFloat64OrFallback(key string, defaultValue float64) (float64, error) {
-	if v, ex := os.LookupEnv(key); ex {
+func (g Getter) GetEnvAsFloat64OrFallback(key string, defaultValue float64) (float64, error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		value, err := strconv.ParseFloat(v, 64)
 		if err != nil {
 			return defaultValue, err
@@ -150,10 +246,23 @@ func GetEnvAsFloat64OrFallback(key string, defaultValue float64) (float64, error
 	return defaultValue, nil
 }
 
-// GetEnvAsBoolOrFallback returns the env variable for the given key,
+// GetEnvAsDurationOrFallback returns the variable (parsed by time.ParseDuration) for
+// the given key and falls back to the given defaultValue if not set
+func (g Getter) GetEnvAsDurationOrFallback(key string, defaultValue time.Duration) (time.Duration, error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
+		value, err := time.ParseDuration(v)
+		if err != nil {
+			return defaultValue, err
+		}
+		return value, nil
+	}
+	return defaultValue, nil
+}
+
+// GetEnvAsBoolOrFallback returns the variable for the given key,
 // parses it as boolean and falls back to the given defaultValue if not set
-func GetEnvAsBoolOrFallback(key string, defaultValue bool) (val bool, err error) {
-	if v, ex := os.LookupEnv(key); ex {
+func (g Getter) GetEnvAsBoolOrFallback(key string, defaultValue bool) (val bool, err error) {
+	if v, ex := g.lookuper.LookupEnv(key); ex {
 		val, err = strconv.ParseBool(v)
 		if err != nil {
 			return
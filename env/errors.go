@@ -0,0 +1,216 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BindError contains all errors which occurred during binding, e.g. missing required variables, invalid values
+// or unsupported types. Errors are ordered by declaration of fields in the structure
+type BindError struct {
+	Errors []error
+}
+
+// Error returns messages of all errors separated by semicolon
+func (e *BindError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the errors matches target
+func (e *BindError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error which matches target, and if one is found, sets target to that error value
+func (e *BindError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingError is returned when required env variable doesn't exist
+type MissingError struct {
+	// Field is the path of the Go field; e.g. Config.Credentials.KeyID
+	Field string
+	// Name is the name of env variable; e.g. CREDENTIALS_KEY_ID
+	Name string
+	// Condition is the rule which requires the variable; e.g. required_if=TLS_ENABLED:true. Empty for require=true
+	Condition string
+}
+
+func (e *MissingError) Error() string {
+	if e.Condition != "" {
+		return fmt.Sprintf("%s is required by %s", e.Name, e.Condition)
+	}
+	return fmt.Sprintf("%s is required", e.Name)
+}
+
+// TagError is returned when env tag has invalid syntax, contains unknown option or the value of the option is invalid;
+// e.g. min=one or prefix=parent
+type TagError struct {
+	// Field is the path of the Go field; e.g. Config.Credentials.KeyID
+	Field string
+	// Tag is the content of env tag
+	Tag string
+	// Column is the position of the error within the tag, starting at 1. Invalid values of options point at the key
+	// of the option
+	Column int
+	// Message describes the error
+	Message string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("invalid tag of %s at column %d: %s", e.Field, e.Column, e.Message)
+}
+
+// VariableError is returned when env variable can't be read; e.g. both NAME and NAME_FILE are set, the file can't
+// be read, references to other variables can't be expanded or indices of slice of structures are not contiguous
+type VariableError struct {
+	// Field is the path of the Go field; e.g. Config.Credentials.KeyID
+	Field string
+	// Name is the name of env variable; e.g. CREDENTIALS_KEY_ID
+	Name string
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *VariableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *VariableError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError is returned when env variable or default value can't be converted to the type of the field
+type ParseError struct {
+	// Field is the path of the Go field; e.g. Config.Credentials.KeyID
+	Field string
+	// Name is the name of env variable; e.g. CREDENTIALS_KEY_ID
+	Name string
+	// Value is the raw value which failed to convert
+	Value string
+	// Default is true if Value comes from default tag
+	Default bool
+	// Type is the type of the field
+	Type reflect.Type
+	// Err is the cause of the error
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Default {
+		return fmt.Sprintf("can't convert default value '%s' of %s to %s: %v", e.Value, e.Name, e.Type, e.Err)
+	}
+	return fmt.Sprintf("can't parse value '%s' of %s to %s: %v", e.Value, e.Name, e.Type, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedTypeError is returned when type of the field can't be bound
+type UnsupportedTypeError struct {
+	// Field is the path of the Go field; e.g. Config.Credentials.KeyID
+	Field string
+	// Name is the name of env variable; e.g. CREDENTIALS_KEY_ID
+	Name string
+	// Type is the type of the field
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported type %s of %s", e.Type, e.Field)
+}
+
+// ValidationError is returned when bound value violates validation rule of the tag; e.g. min=1
+type ValidationError struct {
+	// Field is the path of the Go field; e.g. Config.Credentials.KeyID
+	Field string
+	// Name is the name of env variable; e.g. CREDENTIALS_KEY_ID
+	Name string
+	// Rule is the violated rule; min, max, oneof or pattern
+	Rule string
+	// Param is the parameter of the rule; e.g. 1 for min=1
+	Param string
+	// Value is the bound value which violates the rule
+	Value string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s value '%s' violates %s=%s", e.Name, e.Value, e.Rule, e.Param)
+}
+
+// ExclusiveError is returned when more than one variable of exclusive group is set
+type ExclusiveError struct {
+	// Group is the name of exclusive group
+	Group string
+	// Fields are paths of the Go fields, which are set
+	Fields []string
+	// Names are names of env variables, which are set
+	Names []string
+}
+
+func (e *ExclusiveError) Error() string {
+	return fmt.Sprintf("only one of %s can be set (exclusive=%s)", strings.Join(e.Names, ", "), e.Group)
+}
+
+// DeprecatedError is returned in strict mode instead of warning, when deprecated variable or legacy alias is used
+type DeprecatedError struct {
+	// Field is the path of the Go field; e.g. Config.LogLevel
+	Field string
+	// Name is the name of deprecated env variable
+	Name string
+	// Message describes the deprecation
+	Message string
+}
+
+func (e *DeprecatedError) Error() string {
+	return e.Message
+}
+
+// Warning is reported to the handler set by WithWarnings; e.g. if the value is supplied by legacy alias or
+// deprecated variable. Warnings don't fail binding unless Strict option is used
+type Warning struct {
+	// Field is the path of the Go field; e.g. Config.LogLevel
+	Field string
+	// Name is the name of env variable which caused the warning
+	Name string
+	// Message describes the warning
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expander expands references to variables in values; e.g. postgres://${DB_HOST}:${DB_PORT:-5432}/app
+// Referenced values are expanded recursively, stack contains names of variables being expanded to detect cycles
+type expander struct {
+	lookuper Lookuper
+	stack    []string
+}
+
+// expand expands value of variable name. Supported forms are ${VAR}, ${VAR:-fallback} used if VAR is unset or
+// empty and ${VAR:?message} which returns error if VAR is unset or empty. $$ is expanded to $
+func expand(name, value string, l Lookuper) (string, error) {
+	e := &expander{lookuper: l, stack: []string{name}}
+	return e.expand(value)
+}
+
+func (e *expander) expand(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := closing(s, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("missing '}' in '%s'", s)
+			}
+			v, err := e.reference(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i = end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
+
+// reference resolves content of ${...}
+func (e *expander) reference(ref string) (string, error) {
+	name, op, arg := ref, "", ""
+	if i := strings.Index(ref, ":"); i >= 0 {
+		name, op = ref[:i], ref[i:]
+		if !strings.HasPrefix(op, ":-") && !strings.HasPrefix(op, ":?") {
+			return "", fmt.Errorf("invalid reference '${%s}'", ref)
+		}
+		op, arg = op[:2], op[2:]
+	}
+	if name == "" || strings.ContainsAny(name, " ${}") {
+		return "", fmt.Errorf("invalid reference '${%s}'", ref)
+	}
+	for _, n := range e.stack {
+		if n == name {
+			return "", fmt.Errorf("cycle in references %s -> %s", strings.Join(e.stack, " -> "), name)
+		}
+	}
+	value, found := e.lookuper.LookupEnv(name)
+	if found && value != "" {
+		e.stack = append(e.stack, name)
+		defer func() { e.stack = e.stack[:len(e.stack)-1] }()
+		return e.expand(value)
+	}
+	switch op {
+	case ":-":
+		return e.expand(arg)
+	case ":?":
+		if arg == "" {
+			return "", fmt.Errorf("%s is not set", name)
+		}
+		return "", fmt.Errorf("%s: %s", name, arg)
+	}
+	return "", nil
+}
+
+// closing returns index of '}' closing the reference which starts at start, or -1 if there is none
+func closing(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+	source := MapLookuper{
+		"DB_USER":  "admin",
+		"DB_HOST":  "db.local",
+		"EMPTY":    "",
+		"NESTED":   "${DB_USER}@${DB_HOST}",
+		"CYCLE_A":  "${CYCLE_B}",
+		"CYCLE_B":  "${CYCLE_A}",
+		"SELF":     "x${SELF}",
+		"FALLBACK": "fb",
+	}
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		message  string
+	}{
+		{"plain", "postgres://db/app", "postgres://db/app", ""},
+		{"reference", "postgres://${DB_USER}@${DB_HOST}/app", "postgres://admin@db.local/app", ""},
+		{"unset", "a${UNSET}b", "ab", ""},
+		{"fallback", "${DB_PORT:-5432}", "5432", ""},
+		{"fallback of empty", "${EMPTY:-5432}", "5432", ""},
+		{"fallback not used", "${DB_HOST:-localhost}", "db.local", ""},
+		{"nested fallback", "${UNSET:-${FALLBACK}}", "fb", ""},
+		{"recursive", "postgres://${NESTED}", "postgres://admin@db.local", ""},
+		{"escape", "$$HOME costs $$5 and $", "$HOME costs $5 and $", ""},
+		{"dollar", "price $5", "price $5", ""},
+		{"error", "${DB_PASSWORD:?password must be set}", "", "DB_PASSWORD: password must be set"},
+		{"error without message", "${EMPTY:?}", "", "EMPTY is not set"},
+		{"error not used", "${DB_USER:?must be set}", "admin", ""},
+		{"cycle", "${CYCLE_A}", "", "cycle in references VALUE -> CYCLE_A -> CYCLE_B -> CYCLE_A"},
+		{"self reference", "${SELF}", "", "cycle in references VALUE -> SELF -> SELF"},
+		{"missing brace", "${DB_USER", "", "missing '}' in '${DB_USER'"},
+		{"invalid operator", "${DB_USER:+x}", "", "invalid reference '${DB_USER:+x}'"},
+		{"empty name", "${}", "", "invalid reference '${}'"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := expand("VALUE", test.value, source)
+			if test.message != "" {
+				assert.EqualError(t, err, test.message)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestBindExpand(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		URL      string `env:"DATABASE_URL, expand=true"`
+		Cache    string `env:"CACHE_DIR, default=${HOME}/.cache/app, expand=true"`
+		Raw      string `env:"RAW"`
+		Disabled string `env:"DISABLED, expand=false"`
+		Port     int    `env:"PORT, default=${DB_PORT:-5432}, expand=true"`
+		Required string `env:"REQUIRED, default=${SECRET:?secret is missing}, expand=true"`
+	}
+	source := MapLookuper{
+		"DATABASE_URL": "postgres://${DB_USER}@${DB_HOST}:${DB_PORT:-5432}/app",
+		"DB_USER":      "admin",
+		"DB_HOST":      "db.local",
+		"HOME":         "/home/app",
+		"RAW":          "${HOME}",
+		"DISABLED":     "${HOME}",
+		"SECRET":       "s3cr3t",
+	}
+	tok := &token{}
+	err := BindFrom(source, tok)
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres://admin@db.local:5432/app", tok.URL)
+	assert.Equal(t, "/home/app/.cache/app", tok.Cache)
+	assert.Equal(t, "${HOME}", tok.Raw)
+	assert.Equal(t, "${HOME}", tok.Disabled)
+	assert.Equal(t, 5432, tok.Port)
+	assert.Equal(t, "s3cr3t", tok.Required)
+
+	// global option
+	tok = &token{}
+	err = BindWithOptions(tok, WithLookuper(source), Expand())
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/app", tok.Raw)
+	assert.Equal(t, "${HOME}", tok.Disabled)
+
+	delete(source, "SECRET")
+	err = BindFrom(source, &token{})
+	assert.EqualError(t, err, "can't expand default value of REQUIRED: SECRET: secret is missing")
+	var varErr *VariableError
+	assert.True(t, errors.As(err, &varErr))
+	assert.Equal(t, &VariableError{Field: "token.Required", Name: "REQUIRED", Err: varErr.Err}, varErr)
+
+	// default is not expanded if the variable exists
+	source["REQUIRED"] = "given"
+	tok = &token{}
+	err = BindFrom(source, tok)
+	assert.NoError(t, err)
+	assert.Equal(t, "given", tok.Required)
+}
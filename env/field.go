@@ -18,78 +18,280 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package env
 
 import (
+	"encoding"
+	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
-func boolean(env env) (b bool, err error) {
-	var d bool
-	if env.def.exists {
-		d, err = strconv.ParseBool(env.def.value)
-		if err != nil {
-			err = fmt.Errorf("can't convert default value %s of '%s' to bool", env.name, env.def.value)
-			return
+// parseNumeric parses s and sets numeric value v. Integers are parsed without conversion to float64,
+// so the value is either lossless or error is returned
+func parseNumeric(v reflect.Value, s string) (err error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = parseInt(s, v.Type().Bits(), v.Type().String())
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = parseUint(s, v.Type().Bits(), v.Type().String())
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = parseFloat(s, v.Type().Bits(), v.Type().String())
+		v.SetFloat(f)
+	default:
+		err = fmt.Errorf("%s is not numeric type", v.Type())
+	}
+	return
+}
+
+// parseInt parses signed integer of given bit size; typ is the name of the type used in errors
+func parseInt(s string, bits int, typ string) (i int64, err error) {
+	if i, err = strconv.ParseInt(s, 10, bits); err != nil {
+		return 0, numericError(err, s, typ)
+	}
+	return
+}
+
+// parseUint parses unsigned integer of given bit size; typ is the name of the type used in errors
+func parseUint(s string, bits int, typ string) (u uint64, err error) {
+	if strings.HasPrefix(s, "-") {
+		if _, err = strconv.ParseFloat(s, 64); err == nil {
+			return 0, fmt.Errorf("negative value can't be set to %s", typ)
 		}
 	}
-	b, err = GetEnvAsBoolOrFallback(env.name, d)
+	if u, err = strconv.ParseUint(s, 10, bits); err != nil {
+		return 0, numericError(err, s, typ)
+	}
+	return
+}
+
+// parseFloat parses floating point number of given bit size; typ is the name of the type used in errors
+func parseFloat(s string, bits int, typ string) (f float64, err error) {
+	if f, err = strconv.ParseFloat(s, bits); err != nil {
+		return 0, numericError(err, s, typ)
+	}
 	return
 }
 
-func float(env env) (f float64, err error) {
-	var d float64
-	if env.def.exists {
-		d, err = strconv.ParseFloat(env.def.value, 64)
+// numericError translates strconv errors into readable form
+func numericError(err error, s string, typ string) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("value is out of range of %s", typ)
+	}
+	if _, ferr := strconv.ParseFloat(s, 64); ferr == nil {
+		return fmt.Errorf("floating point value can't be set to %s", typ)
+	}
+	return fmt.Errorf("invalid syntax of %s", typ)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// unmarshalers caches results of isTextUnmarshaler per type
+var unmarshalers sync.Map
+
+// isTextUnmarshaler returns true if type t or pointer to t implements encoding.TextUnmarshaler
+func isTextUnmarshaler(t reflect.Type) bool {
+	if ok, found := unmarshalers.Load(t); found {
+		return ok.(bool)
+	}
+	ok := reflect.PtrTo(t).Implements(textUnmarshalerType)
+	unmarshalers.Store(t, ok)
+	return ok
+}
+
+// unmarshalText creates new value of type t and fills it by UnmarshalText
+func unmarshalText(t reflect.Type, s string) (v reflect.Value, err error) {
+	p := reflect.New(t)
+	err = p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	return p.Elem(), err
+}
+
+// scalar converts env value or default value into the value of type t. Returns zero value if neither env variable
+// nor default exists
+func scalar(env env, t reflect.Type, o *options) (v reflect.Value, err error) {
+	if env.present {
+		v, err = parseScalar(t, env.value, o)
 		if err != nil {
-			err = fmt.Errorf("can't convert default value %s of '%s' to float64", env.name, env.def.value)
-			return
+			err = env.parseError(env.value, false, t, err)
 		}
+		return
+	}
+	if !env.def.exists {
+		return reflect.Zero(t), nil
 	}
-	f, err = GetEnvAsFloat64OrFallback(env.name, d)
+	v, err = parseScalar(t, env.def.value, o)
 	if err != nil {
-		err = fmt.Errorf("can't read %s and parse value '%s' to float64", env.name, env.value)
+		err = env.parseError(env.def.value, true, t, err)
 	}
 	return
 }
 
-func floatSlice(env env) (fs []float64, err error) {
-	var d []float64
-	if env.def.asStringSlice() != nil {
-		d = make([]float64, 0)
-		for _, s := range env.def.asStringSlice() {
-			var fl float64
-			fl, err = strconv.ParseFloat(strings.Trim(s, " "), 64)
-			if err != nil {
-				err = fmt.Errorf("can't convert default %s to slice of float64", env.def.asStringSlice())
+// slice converts env value or default value into slice of scalars. Returns nil if neither env variable
+// nor default exists
+func slice(env env, t reflect.Type, o *options) (v reflect.Value, err error) {
+	items, ok := sliceItems(env)
+	if !ok {
+		return reflect.Zero(t), nil
+	}
+	v = reflect.MakeSlice(t, 0, len(items))
+	for _, s := range items {
+		var item reflect.Value
+		item, err = parseScalar(t.Elem(), strings.TrimSpace(s), o)
+		if err != nil {
+			if env.present {
+				err = env.parseError(env.value, false, t, err)
 				return
 			}
-			d = append(d, fl)
+			err = env.parseError(env.def.value, true, t, err)
+			return
 		}
-	}
-	fs, err = GetEnvAsArrayOfFloat64OrFallback(env.name, d)
-	if err != nil {
-		err = fmt.Errorf("can't parse %s as slice of float64 '%s'", env.name, env.value)
+		v = reflect.Append(v, item)
 	}
 	return
 }
 
-func boolSlice(env env) (bs []bool, err error) {
-	var d []bool
-	if env.def.asStringSlice() != nil {
-		d = make([]bool, 0)
-		for _, s := range env.def.asStringSlice() {
-			var b bool
-			b, err = strconv.ParseBool(strings.Trim(s, " "))
-			if err != nil {
-				err = fmt.Errorf("can't convert default %s to slice of bool", env.def.asStringSlice())
+// strSlice returns slice of strings. Whitespaces are removed from env value, i.e. "us, fr, au" -> {"us","fr","au"}
+func strSlice(env env) []string {
+	if !env.present {
+		return env.def.asStringSlice()
+	}
+	if env.value == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(env.value, " ", ""), ",")
+}
+
+// sliceItems returns raw items of env value or default value. Returns false if neither env variable nor default exists
+func sliceItems(env env) (items []string, ok bool) {
+	switch {
+	case env.present && env.value == "":
+		return []string{}, true
+	case env.present:
+		return strings.Split(env.value, ","), true
+	case env.def.asStringSlice() != nil:
+		return env.def.asStringSlice(), true
+	}
+	return nil, false
+}
+
+// mapping binds map[string]T, where T is any scalar type supported by Bind
+func mapping(env env, t reflect.Type, o *options) (v reflect.Value, err error) {
+	var pairs map[string]string
+	separator, kvSeparator := env.separators()
+	switch {
+	case env.present:
+		pairs, err = splitPairs(env.value, separator, kvSeparator)
+		if err != nil {
+			err = env.parseError(env.value, false, t, err)
+			return
+		}
+	case env.def.exists:
+		pairs, err = env.def.asMap(separator, kvSeparator)
+		if err != nil {
+			err = env.parseError(env.def.value, true, t, err)
+			return
+		}
+	default:
+		return reflect.Zero(t), nil
+	}
+	v = reflect.MakeMapWithSize(t, len(pairs))
+	for k, s := range pairs {
+		var item reflect.Value
+		item, err = parseScalar(t.Elem(), s, o)
+		if err != nil {
+			err = fmt.Errorf("can't convert value '%s' of key '%s' to %s: %w", s, k, t.Elem(), err)
+			if env.present {
+				err = env.parseError(env.value, false, t, err)
 				return
 			}
-			d = append(d, b)
+			err = env.parseError(env.def.value, true, t, err)
+			return
 		}
+		v.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), item)
 	}
-	bs, err = GetEnvAsArrayOfBoolOrFallback(env.name, d)
-	if err != nil {
-		err = fmt.Errorf("can't parse %s as slice of bool '%s'", env.name, env.value)
+	return
+}
+
+// splitPairs splits s into key-value pairs; e.g. "team:core,tier:gold"
+func splitPairs(s, separator, kvSeparator string) (pairs map[string]string, err error) {
+	pairs = map[string]string{}
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	for _, pair := range strings.Split(s, separator) {
+		kv := strings.SplitN(pair, kvSeparator, 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("missing '%s' in '%s'", kvSeparator, pair)
+		}
+		k := strings.TrimSpace(kv[0])
+		if _, found := pairs[k]; found {
+			return nil, fmt.Errorf("duplicate key '%s'", k)
+		}
+		pairs[k] = strings.TrimSpace(kv[1])
+	}
+	return
+}
+
+// parseScalar converts s into value of type t
+func parseScalar(t reflect.Type, s string, o *options) (v reflect.Value, err error) {
+	if p, ok := o.parser(t); ok {
+		return p(t, s)
+	}
+	v = reflect.New(t).Elem()
+	if t == reflect.TypeOf(time.Duration(0)) {
+		var d time.Duration
+		d, err = time.ParseDuration(s)
+		v.SetInt(int64(d))
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		err = parseNumeric(v, s)
+	default:
+		err = fmt.Errorf("unsupported type %s", t)
+	}
+	return
+}
+
+// isScalar returns true if parseScalar can convert string into value of type t
+func isScalar(t reflect.Type, o *options) bool {
+	if _, ok := o.parser(t); ok {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return isInt(t.Kind()) || isUint(t.Kind())
+}
+
+// separators returns pair and key-value separators of map; "," and ":" by default
+func (e env) separators() (separator, kvSeparator string) {
+	separator, kvSeparator = ",", ":"
+	if e.separator.exists && e.separator.value != "" {
+		separator = e.separator.value
+	}
+	if e.kvSeparator.exists && e.kvSeparator.value != "" {
+		kvSeparator = e.kvSeparator.value
 	}
 	return
 }
+
+// parseError creates ParseError of value which can't be converted to type t
+func (e env) parseError(value string, def bool, t reflect.Type, err error) error {
+	return &ParseError{Field: e.field, Name: e.name, Value: value, Default: def, Type: t, Err: err}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validator is implemented by structures which validate themselves; e.g. rules spanning several fields.
+// Validate() is called after all fields are bound
+type Validator interface {
+	Validate() error
+}
+
+// Defaulter is implemented by structures with computed defaults. SetDefaults() is called before binding and
+// fields whose env variables don't exist and have no default keep the values set by it
+type Defaulter interface {
+	SetDefaults()
+}
+
+// validateStructures calls Validate() of structures in meta. Nested structures precede their parents in meta, so
+// they are validated bottom-up. Errors are wrapped by path of the structure
+func validateStructures(m meta) (errs []error) {
+	for _, v := range m {
+		if !v.validator {
+			continue
+		}
+		if err := hook(*v.fieldValue).(Validator).Validate(); err != nil {
+			if v.path != "" {
+				err = fmt.Errorf("%s: %w", v.path, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return
+}
+
+// hook returns pointer to structure value, so its methods can be called even if the structure is not exported
+func hook(value reflect.Value) interface{} {
+	return settable(value).Addr().Interface()
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+// Lookuper retrieves values of environment variables. Bind reads variables from the process environment,
+// BindFrom reads them from any Lookuper, so one process can bind configurations from several sources
+type Lookuper interface {
+	// LookupEnv retrieves the value of the variable named by the key. If the variable is present
+	// the value (which may be empty) is returned and the boolean is true
+	LookupEnv(key string) (string, bool)
+}
+
+// Enumerator is optionally implemented by Lookuper which is able to list names of all its variables.
+// Bind uses it to discover indices of slices of nested structures; without it the indices are probed from 0 until
+// no variable of the element exists, so gaps in indices can't be detected
+type Enumerator interface {
+	// Keys returns names of all variables
+	Keys() []string
+}
+
+// OSLookuper reads variables from the process environment
+type OSLookuper struct{}
+
+// MapLookuper reads variables from map
+type MapLookuper map[string]string
+
+// LookupEnv retrieves the value of the process environment variable
+func (OSLookuper) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// Keys returns names of all process environment variables
+func (OSLookuper) Keys() (keys []string) {
+	for _, kv := range os.Environ() {
+		keys = append(keys, strings.SplitN(kv, "=", 2)[0])
+	}
+	return
+}
+
+// LookupEnv retrieves the value of the variable from map
+func (m MapLookuper) LookupEnv(key string) (v string, ok bool) {
+	v, ok = m[key]
+	return
+}
+
+// Keys returns names of all variables in map
+func (m MapLookuper) Keys() (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// DecoderFunc converts raw value of environment variable (or value of default tag) into the value of registered type
+type DecoderFunc func(string) (interface{}, error)
+
+// Option configures BindWithOptions
+type Option func(*options)
+
+type options struct {
+	decoders  map[reflect.Type]DecoderFunc
+	lookuper  Lookuper
+	files     bool
+	expand    bool
+	fs        fs.FS
+	sources   map[string]string
+	warn      func(Warning)
+	strict    bool
+	autoNames bool
+	prefix    string
+	separator string
+	// rolling counts structures of given type which are being rolled, so recursive types are not expanded forever
+	rolling map[reflect.Type]int
+}
+
+// global decoders registered by RegisterDecoder
+var registry = struct {
+	sync.RWMutex
+	decoders map[reflect.Type]DecoderFunc
+}{decoders: map[reflect.Type]DecoderFunc{}}
+
+// parser converts string into the value of type t
+type parser func(t reflect.Type, s string) (reflect.Value, error)
+
+// RegisterDecoder registers decoder for type t. Decoder is used by every Bind and BindWithOptions call
+// and takes precedence over built-in types and encoding.TextUnmarshaler. Registering nil decoder removes
+// previously registered one.
+func RegisterDecoder(t reflect.Type, d DecoderFunc) {
+	registry.Lock()
+	defer registry.Unlock()
+	if d == nil {
+		delete(registry.decoders, t)
+		return
+	}
+	registry.decoders[t] = d
+}
+
+// WithDecoder registers decoder for type t for single BindWithOptions call. Decoder takes precedence over
+// decoders registered by RegisterDecoder
+func WithDecoder(t reflect.Type, d DecoderFunc) Option {
+	return func(o *options) {
+		if o.decoders == nil {
+			o.decoders = map[reflect.Type]DecoderFunc{}
+		}
+		o.decoders[t] = d
+	}
+}
+
+// WithLookuper sets source of variables; by default variables are read from the process environment
+func WithLookuper(l Lookuper) Option {
+	return func(o *options) {
+		o.lookuper = l
+	}
+}
+
+// FileSecrets enables reading of secrets from files for all fields; e.g. if DB_PASSWORD doesn't exist, its value
+// is read from the file referenced by DB_PASSWORD_FILE. Fields can opt out by file=false
+func FileSecrets() Option {
+	return func(o *options) {
+		o.files = true
+	}
+}
+
+// Expand enables expansion of references to variables in values and defaults of all fields; e.g.
+// postgres://${DB_USER}@${DB_HOST}:${DB_PORT:-5432}/app. Fields can opt out by expand=false
+func Expand() Option {
+	return func(o *options) {
+		o.expand = true
+	}
+}
+
+// WithSources records name of env variable, which supplied the value, for every bound field. The map is keyed
+// by path of the field; e.g. sources["Config.LogLevel"] == "LOG_LEVEL" if the value was supplied by alias
+func WithSources(sources map[string]string) Option {
+	return func(o *options) {
+		o.sources = sources
+	}
+}
+
+// WithWarnings sets handler of warnings; e.g. the value supplied by legacy alias or deprecated variable
+func WithWarnings(handler func(Warning)) Option {
+	return func(o *options) {
+		o.warn = handler
+	}
+}
+
+// Strict turns warnings into errors, so binding fails if deprecated variable or legacy alias is used
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// AutoNames derives names of env variables of exported fields without name in the tag from field names; e.g.
+// MaxIdleConns is bound from MAX_IDLE_CONNS. Explicit name in the tag takes precedence and env:"-" skips the field
+func AutoNames() Option {
+	return func(o *options) {
+		o.autoNames = true
+	}
+}
+
+// WithPrefix prepends prefix to names of all env variables; e.g. ORDERS_PORT instead of PORT for WithPrefix("ORDERS").
+// Fields can opt out by noprefix=true
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithSeparator sets separator which joins prefixes, names and indices of env variables; e.g. WithSeparator("__")
+// binds PRIMARY__URL instead of PRIMARY_URL. Default separator is "_"
+func WithSeparator(separator string) Option {
+	return func(o *options) {
+		if separator != "" {
+			o.separator = separator
+		}
+	}
+}
+
+// WithFS sets filesystem used for reading secrets from files. Paths are passed to the filesystem without leading
+// slash, because fs.FS paths are unrooted; e.g. /run/secrets/db is read as run/secrets/db. By default, files are
+// read from the operating system
+func WithFS(fsys fs.FS) Option {
+	return func(o *options) {
+		o.fs = fsys
+	}
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{lookuper: OSLookuper{}, separator: "_", rolling: map[reflect.Type]int{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.prefix = strings.Trim(o.prefix, o.separator)
+	return o
+}
+
+// readFile reads file from filesystem configured by WithFS or from the operating system
+func (o *options) readFile(path string) ([]byte, error) {
+	if o.fs == nil {
+		// #nosec G304; path of the secret is provided by the environment
+		return os.ReadFile(path)
+	}
+	return fs.ReadFile(o.fs, strings.TrimPrefix(path, "/"))
+}
+
+// decoder returns decoder registered for type t
+func (o *options) decoder(t reflect.Type) (d DecoderFunc, ok bool) {
+	if d, ok = o.decoders[t]; ok {
+		return
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	d, ok = registry.decoders[t]
+	return
+}
+
+// parser returns parser for the types which are not bound by built-in conversions; e.g. types with registered
+// decoder or types implementing encoding.TextUnmarshaler
+func (o *options) parser(t reflect.Type) (p parser, ok bool) {
+	var d DecoderFunc
+	if d, ok = o.decoder(t); ok {
+		return d.parse, true
+	}
+	if isTextUnmarshaler(t) {
+		return unmarshalText, true
+	}
+	return nil, false
+}
+
+func (d DecoderFunc) parse(t reflect.Type, s string) (v reflect.Value, err error) {
+	var i interface{}
+	i, err = d(s)
+	if err != nil {
+		return
+	}
+	if i == nil {
+		return reflect.Zero(t), nil
+	}
+	v = reflect.ValueOf(i)
+	if !v.Type().AssignableTo(t) {
+		err = fmt.Errorf("decoder returned %s instead of %s", v.Type(), t)
+	}
+	return
+}
+
+// supplied records source of the value of field v and reports warnings if the value was supplied by legacy alias
+// or deprecated variable. Warnings are returned as DeprecatedError in strict mode
+func (o *options) supplied(v field) error {
+	var warnings []Warning
+	if o.sources != nil {
+		o.sources[v.path] = v.env.source
+	}
+	if v.env.source != v.env.name {
+		warnings = append(warnings, Warning{Field: v.path, Name: v.env.source,
+			Message: fmt.Sprintf("%s is deprecated, use %s", v.env.source, v.env.name)})
+	}
+	if v.env.deprecated.exists {
+		message := fmt.Sprintf("%s is deprecated", v.env.source)
+		if v.env.deprecated.value != "" {
+			message = fmt.Sprintf("%s: %s", message, v.env.deprecated.value)
+		}
+		warnings = append(warnings, Warning{Field: v.path, Name: v.env.source, Message: message})
+	}
+	for _, w := range warnings {
+		if o.strict {
+			return &DeprecatedError{Field: w.Field, Name: w.Name, Message: w.Message}
+		}
+		if o.warn != nil {
+			o.warn(w)
+		}
+	}
+	return nil
+}
+
+// unprefixed removes prefix set by WithPrefix from the beginning of prefix of nested structure
+func (o *options) unprefixed(prefix string) string {
+	if o.prefix == "" || prefix == o.prefix {
+		return strings.TrimPrefix(prefix, o.prefix)
+	}
+	return strings.TrimPrefix(prefix, o.prefix+o.separator)
+}
+
+// join joins prefix and name by separator; e.g. PRIMARY_URL
+func (o *options) join(prefix, name string) string {
+	switch {
+	case prefix == "":
+		return name
+	case name == "":
+		return prefix
+	}
+	return prefix + o.separator + name
+}
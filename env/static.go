@@ -0,0 +1,495 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StaticBinder binds variables in binders generated by 12f-gen. It applies the rules of Bind; i.e. require,
+// default, protected and Defaulter / Validator hooks, but values are converted by type switches instead of reflection.
+// Errors are collected, so the generated binder returns the same BindError as Bind
+type StaticBinder struct {
+	lookuper   Lookuper
+	errs       []error
+	validators []staticValidator
+}
+
+// Spec describes the field bound by StaticBinder
+type Spec struct {
+	// Field is the path of the Go field; e.g. Config.Credentials.KeyID
+	Field string
+	// Name is the name of env variable including prefixes; e.g. CREDENTIALS_KEY_ID
+	Name string
+	// Default is the value of default tag, used if HasDefault is true
+	Default    string
+	HasDefault bool
+	// Require is true for require=true
+	Require bool
+	// Protected is true if the field is protected and already has a value, so it is not overwritten
+	Protected bool
+	// Defaulted is true if the structure of the field implements Defaulter, so the field keeps its value if
+	// env variable doesn't exist and has no default
+	Defaulted bool
+}
+
+type staticValidator struct {
+	path      string
+	validator Validator
+}
+
+// staticValue is the value of env variable or default value described by Spec
+type staticValue struct {
+	Spec
+	value   string
+	present bool
+}
+
+// NewStaticBinder creates StaticBinder reading variables from l
+func NewStaticBinder(l Lookuper) (b *StaticBinder, err error) {
+	if l == nil {
+		return nil, fmt.Errorf("invalid lookuper (nil)")
+	}
+	return &StaticBinder{lookuper: l}, nil
+}
+
+// Bind binds env variable described by s into the field referenced by p. Supported are built-in types of Bind;
+// i.e. string, bool, integers, floats, time.Duration, slices of them and pointers to them
+func (b *StaticBinder) Bind(s Spec, p interface{}) {
+	v := staticValue{Spec: s}
+	v.value, v.present = b.lookuper.LookupEnv(s.Name)
+	switch {
+	case !v.present && s.Require:
+		b.errs = append(b.errs, &MissingError{Field: s.Field, Name: s.Name})
+		return
+	case !v.present && !s.HasDefault && s.Defaulted:
+		return
+	case s.Protected:
+		return
+	}
+	if err := v.set(p); err != nil {
+		b.errs = append(b.errs, err)
+	}
+}
+
+// Exists returns true if any of env variables exists; generated binders allocate nil pointers to nested
+// structures only if some of their variables exist
+func (b *StaticBinder) Exists(names ...string) bool {
+	for _, name := range names {
+		if _, found := b.lookuper.LookupEnv(name); found {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate registers structure, which is validated by Done. Nested structures must be registered before parents,
+// so they are validated bottom-up
+func (b *StaticBinder) Validate(path string, v Validator) {
+	b.validators = append(b.validators, staticValidator{path: path, validator: v})
+}
+
+// Done calls Validate() of registered structures if all fields were bound successfully and returns BindError
+// containing all errors; nil if there are no errors
+func (b *StaticBinder) Done() error {
+	errs := b.errs
+	if len(errs) == 0 {
+		for _, v := range b.validators {
+			if err := v.validator.Validate(); err != nil {
+				if v.path != "" {
+					err = fmt.Errorf("%s: %w", v.path, err)
+				}
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return &BindError{Errors: errs}
+	}
+	return nil
+}
+
+// set converts the value and stores it into field p. The field is set to zero value if neither env variable nor
+// default exists. The field is kept untouched if the value can't be converted
+func (v staticValue) set(p interface{}) (err error) {
+	exists := v.present || v.HasDefault
+	raw := v.value
+	if !v.present {
+		raw = v.Default
+	}
+	if x, store, ok := staticScalar(p); ok {
+		if exists {
+			if err = parseStatic(x, raw); err != nil {
+				return v.parseError(raw, p, err)
+			}
+		}
+		store()
+		return
+	}
+	if x, store, ok := staticPointer(p, exists); ok {
+		if exists {
+			if err = parseStatic(x, raw); err != nil {
+				return v.parseError(raw, x, err)
+			}
+		}
+		store()
+		return
+	}
+	items := v.items(p)
+	if elems, store, ok := staticSlice(p, items, exists); ok {
+		for i, item := range items {
+			if err = parseStatic(elems[i], item); err != nil {
+				return v.parseError(raw, p, err)
+			}
+		}
+		store()
+		return
+	}
+	return &UnsupportedTypeError{Field: v.Field, Name: v.Name, Type: reflect.TypeOf(p).Elem()}
+}
+
+// items returns raw items of slice; whitespaces are removed from items of []string and trimmed for other slices
+func (v staticValue) items(p interface{}) (items []string) {
+	def := strTag{value: v.Default, exists: v.HasDefault}
+	if _, ok := p.(*[]string); ok {
+		return strSlice(env{value: v.value, present: v.present, def: def})
+	}
+	items, _ = sliceItems(env{value: v.value, present: v.present, def: def})
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return
+}
+
+// parseError creates ParseError of raw value, p is pointer to the field
+func (v staticValue) parseError(raw string, p interface{}, err error) error {
+	return &ParseError{Field: v.Field, Name: v.Name, Value: raw, Default: !v.present, Type: reflect.TypeOf(p).Elem(),
+		Err: err}
+}
+
+// parseStatic parses s into scalar referenced by p
+func parseStatic(p interface{}, s string) (err error) {
+	var i int64
+	var u uint64
+	var f float64
+	switch p := p.(type) {
+	case *string:
+		*p = s
+	case *bool:
+		*p, err = strconv.ParseBool(s)
+	case *time.Duration:
+		*p, err = time.ParseDuration(s)
+	case *int:
+		i, err = parseInt(s, strconv.IntSize, "int")
+		*p = int(i)
+	case *int8:
+		i, err = parseInt(s, 8, "int8")
+		*p = int8(i)
+	case *int16:
+		i, err = parseInt(s, 16, "int16")
+		*p = int16(i)
+	case *int32:
+		i, err = parseInt(s, 32, "int32")
+		*p = int32(i)
+	case *int64:
+		*p, err = parseInt(s, 64, "int64")
+	case *uint:
+		u, err = parseUint(s, strconv.IntSize, "uint")
+		*p = uint(u)
+	case *uint8:
+		u, err = parseUint(s, 8, "uint8")
+		*p = uint8(u)
+	case *uint16:
+		u, err = parseUint(s, 16, "uint16")
+		*p = uint16(u)
+	case *uint32:
+		u, err = parseUint(s, 32, "uint32")
+		*p = uint32(u)
+	case *uint64:
+		*p, err = parseUint(s, 64, "uint64")
+	case *float32:
+		f, err = parseFloat(s, 32, "float32")
+		*p = float32(f)
+	case *float64:
+		*p, err = parseFloat(s, 64, "float64")
+	default:
+		err = fmt.Errorf("unsupported type %T", p)
+	}
+	return
+}
+
+// staticScalar returns pointer to temporary scalar of the type referenced by p and function storing it into p
+func staticScalar(p interface{}) (x interface{}, store func(), ok bool) {
+	switch p := p.(type) {
+	case *string:
+		x := new(string)
+		return x, func() { *p = *x }, true
+	case *bool:
+		x := new(bool)
+		return x, func() { *p = *x }, true
+	case *time.Duration:
+		x := new(time.Duration)
+		return x, func() { *p = *x }, true
+	case *int:
+		x := new(int)
+		return x, func() { *p = *x }, true
+	case *int8:
+		x := new(int8)
+		return x, func() { *p = *x }, true
+	case *int16:
+		x := new(int16)
+		return x, func() { *p = *x }, true
+	case *int32:
+		x := new(int32)
+		return x, func() { *p = *x }, true
+	case *int64:
+		x := new(int64)
+		return x, func() { *p = *x }, true
+	case *uint:
+		x := new(uint)
+		return x, func() { *p = *x }, true
+	case *uint8:
+		x := new(uint8)
+		return x, func() { *p = *x }, true
+	case *uint16:
+		x := new(uint16)
+		return x, func() { *p = *x }, true
+	case *uint32:
+		x := new(uint32)
+		return x, func() { *p = *x }, true
+	case *uint64:
+		x := new(uint64)
+		return x, func() { *p = *x }, true
+	case *float32:
+		x := new(float32)
+		return x, func() { *p = *x }, true
+	case *float64:
+		x := new(float64)
+		return x, func() { *p = *x }, true
+	}
+	return nil, nil, false
+}
+
+// staticPointer allocates scalar of the type referenced by pointer p, if the variable exists. The function stores
+// allocated scalar or nil into p
+func staticPointer(p interface{}, exists bool) (x interface{}, store func(), ok bool) {
+	switch p := p.(type) {
+	case **string:
+		x := new(string)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **bool:
+		x := new(bool)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **time.Duration:
+		x := new(time.Duration)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **int:
+		x := new(int)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **int8:
+		x := new(int8)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **int16:
+		x := new(int16)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **int32:
+		x := new(int32)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **int64:
+		x := new(int64)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **uint:
+		x := new(uint)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **uint8:
+		x := new(uint8)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **uint16:
+		x := new(uint16)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **uint32:
+		x := new(uint32)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **uint64:
+		x := new(uint64)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **float32:
+		x := new(float32)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	case **float64:
+		x := new(float64)
+		if !exists {
+			x = nil
+		}
+		return x, func() { *p = x }, true
+	}
+	return nil, nil, false
+}
+
+// staticSlice allocates slice of the type referenced by p with one element per item, if the variable exists.
+// It returns pointers to the elements and the function storing allocated slice or nil into p
+func staticSlice(p interface{}, items []string, exists bool) (elems []interface{}, store func(), ok bool) {
+	switch p := p.(type) {
+	case *[]string:
+		s := make([]string, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]bool:
+		s := make([]bool, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]time.Duration:
+		s := make([]time.Duration, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]int:
+		s := make([]int, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]int8:
+		s := make([]int8, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]int16:
+		s := make([]int16, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]int32:
+		s := make([]int32, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]int64:
+		s := make([]int64, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]uint:
+		s := make([]uint, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]uint8:
+		s := make([]uint8, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]uint16:
+		s := make([]uint16, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]uint32:
+		s := make([]uint32, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]uint64:
+		s := make([]uint64, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]float32:
+		s := make([]float32, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	case *[]float64:
+		s := make([]float64, len(items))
+		if !exists {
+			s = nil
+		}
+		return staticElems(len(s), func(i int) interface{} { return &s[i] }), func() { *p = s }, true
+	}
+	return nil, nil, false
+}
+
+// staticElems returns pointers to n elements of slice; at returns pointer to the i-th element
+func staticElems(n int, at func(int) interface{}) (elems []interface{}) {
+	elems = make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		elems = append(elems, at(i))
+	}
+	return
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticConfig struct {
+	String    string          `env:"STRING"`
+	Bool      bool            `env:"BOOL"`
+	Duration  time.Duration   `env:"DURATION"`
+	Int       int             `env:"INT"`
+	Int8      int8            `env:"INT8"`
+	Int16     int16           `env:"INT16"`
+	Int32     int32           `env:"INT32"`
+	Int64     int64           `env:"INT64"`
+	Uint      uint            `env:"UINT"`
+	Uint8     uint8           `env:"UINT8"`
+	Uint16    uint16          `env:"UINT16"`
+	Uint32    uint32          `env:"UINT32"`
+	Uint64    uint64          `env:"UINT64"`
+	Float32   float32         `env:"FLOAT32"`
+	Float64   float64         `env:"FLOAT64"`
+	Strings   []string        `env:"STRINGS"`
+	Bools     []bool          `env:"BOOLS"`
+	Durations []time.Duration `env:"DURATIONS"`
+	Int16s    []int16         `env:"INT16S"`
+	Uint32s   []uint32        `env:"UINT32S"`
+	Float32s  []float32       `env:"FLOAT32S"`
+	PString   *string         `env:"PSTRING"`
+	PDuration *time.Duration  `env:"PDURATION"`
+	PInt64    *int64          `env:"PINT64"`
+	PUint     *uint           `env:"PUINT"`
+	PFloat64  *float64        `env:"PFLOAT64"`
+}
+
+// bindStatic binds staticConfig by StaticBinder the same way as generated binder does
+func bindStatic(c *staticConfig, l Lookuper) error {
+	b, err := NewStaticBinder(l)
+	if err != nil {
+		return err
+	}
+	fields := []struct {
+		name string
+		p    interface{}
+	}{
+		{"String", &c.String}, {"Bool", &c.Bool}, {"Duration", &c.Duration}, {"Int", &c.Int}, {"Int8", &c.Int8},
+		{"Int16", &c.Int16}, {"Int32", &c.Int32}, {"Int64", &c.Int64}, {"Uint", &c.Uint}, {"Uint8", &c.Uint8},
+		{"Uint16", &c.Uint16}, {"Uint32", &c.Uint32}, {"Uint64", &c.Uint64}, {"Float32", &c.Float32},
+		{"Float64", &c.Float64}, {"Strings", &c.Strings}, {"Bools", &c.Bools}, {"Durations", &c.Durations},
+		{"Int16s", &c.Int16s}, {"Uint32s", &c.Uint32s}, {"Float32s", &c.Float32s}, {"PString", &c.PString},
+		{"PDuration", &c.PDuration}, {"PInt64", &c.PInt64}, {"PUint", &c.PUint}, {"PFloat64", &c.PFloat64},
+	}
+	for _, f := range fields {
+		b.Bind(Spec{Field: "staticConfig." + f.name, Name: strings.ToUpper(f.name)}, f.p)
+	}
+	return b.Done()
+}
+
+func TestStaticBinder(t *testing.T) {
+	t.Parallel()
+	tests := []MapLookuper{
+		{},
+		{"STRING": "a", "BOOL": "true", "DURATION": "1m", "INT": "-1", "INT8": "-8", "INT16": "16", "INT32": "32",
+			"INT64": "9223372036854775807", "UINT": "1", "UINT8": "255", "UINT16": "16", "UINT32": "32",
+			"UINT64": "18446744073709551615", "FLOAT32": "3.2", "FLOAT64": "6.4", "STRINGS": "a, b", "BOOLS": "true",
+			"DURATIONS": "1s, 2m", "INT16S": "1,2", "UINT32S": "3", "FLOAT32S": "0.5", "PSTRING": "",
+			"PDURATION": "1h", "PINT64": "0", "PUINT": "7", "PFLOAT64": "1.5"},
+		{"STRINGS": "", "BOOLS": "", "INT16S": "", "PSTRING": "x"},
+		{"BOOL": "2", "DURATION": "1", "INT": "1.5", "INT8": "128", "INT16": "x", "INT32": "2147483648",
+			"INT64": "9223372036854775808", "UINT": "-1", "UINT8": "256", "UINT16": "65536", "UINT32": "-0",
+			"UINT64": "18446744073709551616", "FLOAT32": "1e39", "FLOAT64": "x", "BOOLS": "x", "DURATIONS": "1s,x",
+			"INT16S": "1,40000", "UINT32S": "-3", "FLOAT32S": "x", "PDURATION": "x", "PINT64": "1.0", "PUINT": "-7",
+			"PFLOAT64": "-"},
+	}
+	for _, test := range tests {
+		expected, actual := &staticConfig{}, &staticConfig{}
+		expectedErr := BindFrom(test, expected)
+		actualErr := bindStatic(actual, test)
+		assert.Equal(t, expected, actual)
+		if expectedErr == nil {
+			assert.NoError(t, actualErr)
+			continue
+		}
+		assert.EqualError(t, actualErr, expectedErr.Error())
+		assert.Equal(t, expectedErr.(*BindError).Errors, actualErr.(*BindError).Errors)
+	}
+}
+
+func TestStaticBinderSpec(t *testing.T) {
+	t.Parallel()
+	_, err := NewStaticBinder(nil)
+	assert.EqualError(t, err, "invalid lookuper (nil)")
+
+	b, _ := NewStaticBinder(MapLookuper{"PORT": "80", "HOSTS": "a,b"})
+	port, hosts, name, level, label := 1, []string{"c"}, "n", "warn", "x"
+	b.Bind(Spec{Field: "Config.Port", Name: "PORT", Protected: port != 0}, &port)
+	b.Bind(Spec{Field: "Config.Hosts", Name: "HOSTS", Default: "[d]", HasDefault: true}, &hosts)
+	b.Bind(Spec{Field: "Config.Name", Name: "NAME", Require: true}, &name)
+	b.Bind(Spec{Field: "Config.Level", Name: "LEVEL", Defaulted: true}, &level)
+	b.Bind(Spec{Field: "Config.Label", Name: "LABEL"}, &label)
+	b.Bind(Spec{Field: "Config.Labels", Name: "PORT"}, &map[string]string{})
+	assert.Equal(t, 1, port)
+	assert.Equal(t, []string{"a", "b"}, hosts)
+	assert.Equal(t, "n", name)
+	assert.Equal(t, "warn", level)
+	assert.Equal(t, "", label)
+	assert.True(t, b.Exists("LEVEL", "HOSTS"))
+	assert.False(t, b.Exists("LEVEL"))
+	b.Validate("Config", failedValidator{})
+	err = b.Done()
+	assert.EqualError(t, err, "NAME is required; unsupported type map[string]string of Config.Labels")
+	assert.ErrorAs(t, err, new(*MissingError))
+	assert.ErrorAs(t, err, new(*UnsupportedTypeError))
+}
+
+type failedValidator struct{}
+
+func (failedValidator) Validate() error {
+	panic("structures are not validated if binding fails")
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tagOptions contains options of env tag, other options are rejected
+var tagOptions = map[string]bool{
+	"default": true, "require": true, "protected": true, "separator": true, "kvseparator": true, "file": true,
+	"min": true, "max": true, "oneof": true, "pattern": true, "required_if": true, "required_unless": true,
+	"exclusive": true, "expand": true, "deprecated": true, "noprefix": true, "prefix": true,
+}
+
+// structTag is parsed env tag; e.g. APP_LOG_LEVEL|LOG_LEVEL, default=info
+type structTag struct {
+	// names contains name of env variable followed by its aliases
+	names   []string
+	options map[string]strTag
+}
+
+// name returns name of env variable; empty if the tag contains options only
+func (t structTag) name() string {
+	if len(t.names) == 0 {
+		return ""
+	}
+	return t.names[0]
+}
+
+// option returns value of the option
+func (t structTag) option(key string) strTag {
+	return t.options[key]
+}
+
+// tagParser parses env tag. The grammar of the tag is
+//
+//	tag     = [ name { "|" name } ] { "," option }
+//	option  = key "=" value
+//	value   = quoted | raw
+//
+// Quoted values are enclosed in double or single quotes and support escape sequences \" \' \\ \n \r \t.
+// Raw values end by comma outside of brackets [] and braces {}, so lists and maps don't need quotes; e.g.
+// default=[a,b] or default={a:1,b:2}. \, is a comma in raw values. Spaces around names, keys and raw values are
+// trimmed
+type tagParser struct {
+	field string
+	tag   string
+	pos   int
+}
+
+// parseStructTag parses env tag of the field
+func parseStructTag(field, tag string) (t structTag, err error) {
+	p := &tagParser{field: field, tag: tag}
+	t.options = map[string]strTag{}
+	if t.names, err = p.names(); err != nil {
+		return
+	}
+	for !p.end() {
+		// p.pos points at comma
+		p.pos++
+		start := p.skipSpaces()
+		var key string
+		var value strTag
+		if key, err = p.key(); err != nil {
+			return
+		}
+		if !tagOptions[key] {
+			return t, p.error(start, "unknown option %s", key)
+		}
+		if _, found := t.options[key]; found {
+			return t, p.error(start, "duplicate option %s", key)
+		}
+		if value, err = p.value(); err != nil {
+			return
+		}
+		value.column = start + 1
+		t.options[key] = value
+	}
+	return
+}
+
+// ParseTag parses env tag of the field the same way as Bind does; e.g. names [APP_LOG_LEVEL LOG_LEVEL] and options
+// map[default:info] for "APP_LOG_LEVEL|LOG_LEVEL, default=info". It is intended for tools like 12f-gen. Syntax errors
+// are returned as TagError
+func ParseTag(field, tag string) (names []string, options map[string]string, err error) {
+	var t structTag
+	if t, err = parseStructTag(field, tag); err != nil {
+		return nil, nil, err
+	}
+	options = make(map[string]string, len(t.options))
+	for k, v := range t.options {
+		options[k] = v.value
+	}
+	return t.names, options, nil
+}
+
+// names parses name of env variable and its aliases
+func (p *tagParser) names() (names []string, err error) {
+	p.skipSpaces()
+	if p.end() || p.peek() == ',' {
+		return
+	}
+	for {
+		start := p.skipSpaces()
+		name := p.identifier()
+		if name == "" {
+			if p.end() || p.peek() == '|' || p.peek() == ',' {
+				return nil, p.error(start, "missing name")
+			}
+			return nil, p.error(p.pos, "invalid character %q in name", p.peek())
+		}
+		names = append(names, name)
+		p.skipSpaces()
+		switch {
+		case p.end() || p.peek() == ',':
+			return
+		case p.peek() == '|':
+			p.pos++
+		default:
+			return nil, p.error(p.pos, "invalid character %q in name", p.peek())
+		}
+	}
+}
+
+// key parses key of the option including '='
+func (p *tagParser) key() (key string, err error) {
+	start := p.pos
+	key = p.identifier()
+	if key == "" {
+		if p.end() || p.peek() == ',' {
+			return "", p.error(start, "missing option")
+		}
+		return "", p.error(p.pos, "invalid character %q in option", p.peek())
+	}
+	p.skipSpaces()
+	if p.end() || p.peek() != '=' {
+		return "", p.error(p.pos, "missing '=' after %s", key)
+	}
+	p.pos++
+	return
+}
+
+// value parses quoted or raw value of the option
+func (p *tagParser) value() (v strTag, err error) {
+	p.skipSpaces()
+	if !p.end() && (p.peek() == '"' || p.peek() == '\'') {
+		if v.value, err = p.quoted(); err != nil {
+			return
+		}
+		p.skipSpaces()
+		if !p.end() && p.peek() != ',' {
+			return v, p.error(p.pos, "unexpected character %q after quoted value", p.peek())
+		}
+		v.exists = true
+		return
+	}
+	v.value, err = p.raw()
+	v.exists = err == nil
+	return
+}
+
+func (p *tagParser) quoted() (string, error) {
+	var b strings.Builder
+	start := p.pos
+	quote := p.tag[p.pos]
+	p.pos++
+	for !p.end() {
+		c := p.tag[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.end() {
+				return "", p.error(start, "missing closing %c", quote)
+			}
+			switch e := p.tag[p.pos]; e {
+			case '"', '\'', '\\':
+				b.WriteByte(e)
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				return "", p.error(p.pos-1, "invalid escape sequence \\%c", e)
+			}
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.error(start, "missing closing %c", quote)
+}
+
+func (p *tagParser) raw() (string, error) {
+	var b strings.Builder
+	var open []int
+	for ; !p.end(); p.pos++ {
+		c := p.tag[p.pos]
+		switch {
+		case c == ',' && len(open) == 0:
+			return strings.TrimSpace(b.String()), nil
+		case c == '\\' && p.pos+1 < len(p.tag) && p.tag[p.pos+1] == ',':
+			p.pos++
+			c = ','
+		case c == '[' || c == '{':
+			open = append(open, p.pos)
+		case (c == ']' || c == '}') && len(open) != 0:
+			if expected := closingBracket(p.tag[open[len(open)-1]]); c != expected {
+				return "", p.error(p.pos, "expected %q, found %q", expected, c)
+			}
+			open = open[:len(open)-1]
+		}
+		b.WriteByte(c)
+	}
+	if len(open) != 0 {
+		pos := open[len(open)-1]
+		return "", p.error(pos, "missing %q closing %q", closingBracket(p.tag[pos]), p.tag[pos])
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// identifier parses name of env variable or key of the option
+func (p *tagParser) identifier() string {
+	start := p.pos
+	for ; !p.end(); p.pos++ {
+		c := p.tag[p.pos]
+		letter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !letter && (p.pos == start || c < '0' || c > '9') {
+			break
+		}
+	}
+	return p.tag[start:p.pos]
+}
+
+// skipSpaces moves position behind spaces and returns it
+func (p *tagParser) skipSpaces() int {
+	for !p.end() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+	return p.pos
+}
+
+func (p *tagParser) peek() byte {
+	return p.tag[p.pos]
+}
+
+func (p *tagParser) end() bool {
+	return p.pos >= len(p.tag)
+}
+
+// error returns TagError pointing at column of position pos
+func (p *tagParser) error(pos int, format string, args ...interface{}) error {
+	return &TagError{Field: p.field, Tag: p.tag, Column: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+func closingBracket(c byte) byte {
+	if c == '[' {
+		return ']'
+	}
+	return '}'
+}
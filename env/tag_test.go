@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStructTag(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tag     string
+		names   []string
+		options map[string]string
+	}{
+		{"", nil, map[string]string{}},
+		{"PORT", []string{"PORT"}, map[string]string{}},
+		{" APP_LOG_LEVEL | LOG_LEVEL ,default = info ", []string{"APP_LOG_LEVEL", "LOG_LEVEL"},
+			map[string]string{"default": "info"}},
+		{", default=2", nil, map[string]string{"default": "2"}},
+		{"HOSTS, default=[a, b], require=true", []string{"HOSTS"},
+			map[string]string{"default": "[a, b]", "require": "true"}},
+		{"LABELS, default={a:1,b:[2,3]}", []string{"LABELS"}, map[string]string{"default": "{a:1,b:[2,3]}"}},
+		{"NAME, pattern=^[a-z]{2,3}$", []string{"NAME"}, map[string]string{"pattern": "^[a-z]{2,3}$"}},
+		{`NAME, default="a,b", deprecated='use "NAME_V2"'`, []string{"NAME"},
+			map[string]string{"default": "a,b", "deprecated": `use "NAME_V2"`}},
+		{`NAME, default="a\"b\\c\n\t'"`, []string{"NAME"}, map[string]string{"default": "a\"b\\c\n\t'"}},
+		{`NAME, default=a\,b`, []string{"NAME"}, map[string]string{"default": "a,b"}},
+		{`NAME, default=a]b=c\d`, []string{"NAME"}, map[string]string{"default": `a]b=c\d`}},
+		{"NAME, default=", []string{"NAME"}, map[string]string{"default": ""}},
+		{"NAME, separator=;, kvseparator==", []string{"NAME"}, map[string]string{"separator": ";", "kvseparator": "="}},
+	}
+	for _, test := range tests {
+		tag, err := parseStructTag("Config.Field", test.tag)
+		assert.NoError(t, err, test.tag)
+		assert.Equal(t, test.names, tag.names, test.tag)
+		options := map[string]string{}
+		for k, v := range tag.options {
+			assert.True(t, v.exists)
+			options[k] = v.value
+		}
+		assert.Equal(t, test.options, options, test.tag)
+		// exported parser returns the same names and options
+		names, options, err := ParseTag("Config.Field", test.tag)
+		assert.NoError(t, err, test.tag)
+		assert.Equal(t, test.names, names, test.tag)
+		assert.Equal(t, test.options, options, test.tag)
+	}
+}
+
+func TestParseStructTagErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tag     string
+		column  int
+		message string
+	}{
+		{"GG%%^", 3, "invalid character '%' in name"},
+		{"A||B", 3, "missing name"},
+		{"A|", 3, "missing name"},
+		{"A B", 3, "invalid character 'B' in name"},
+		{"A, requried=true", 4, "unknown option requried"},
+		{"A, default=1, default=2", 15, "duplicate option default"},
+		{"A, default[]", 11, "missing '=' after default"},
+		{"A, require", 11, "missing '=' after require"},
+		{"A,", 3, "missing option"},
+		{"A,, default=1", 3, "missing option"},
+		{"A, -x=1", 4, "invalid character '-' in option"},
+		{"A, default=[a,b", 12, "missing ']' closing '['"},
+		{"A, default={a:[1}", 17, "expected ']', found '}'"},
+		{`A, default="a,b`, 12, "missing closing \""},
+		{`A, default="a" b`, 16, "unexpected character 'b' after quoted value"},
+		{`A, default="a\qb"`, 14, "invalid escape sequence \\q"},
+	}
+	for _, test := range tests {
+		_, err := parseStructTag("Config.Field", test.tag)
+		var tagErr *TagError
+		assert.True(t, errors.As(err, &tagErr), test.tag)
+		assert.Equal(t, &TagError{Field: "Config.Field", Tag: test.tag, Column: test.column, Message: test.message},
+			tagErr, test.tag)
+	}
+}
+
+func TestBindTagError(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		Port  int    `env:"PORT, requried=true"`
+		Name  string `env:"NAME, default=\"orders, inc.\""`
+		Other int    `env:"OTHER, default=1"`
+	}
+	tok := &token{}
+	err := BindFrom(MapLookuper{}, tok)
+	assert.EqualError(t, err, "invalid tag of token.Port at column 7: unknown option requried")
+	assert.Equal(t, "orders, inc.", tok.Name)
+	assert.Equal(t, 1, tok.Other)
+
+	// errors of tags are reported even if nil pointer to nested structure stays nil
+	type endpoint struct {
+		URL string `env:"URL, requried=true"`
+	}
+	type parent struct {
+		Primary *endpoint `env:"PRIMARY"`
+	}
+	p := &parent{}
+	err = BindFrom(MapLookuper{}, p)
+	assert.EqualError(t, err, "invalid tag of parent.Primary.URL at column 6: unknown option requried")
+	assert.Nil(t, p.Primary)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// patterns contains compiled regular expressions of pattern rule
+var patterns sync.Map
+
+// validate checks bound value of the field against min, max, oneof and pattern rules. Fields without env value
+// and without default are not validated
+func validate(f reflect.Value, v field) (err error) {
+	if !v.env.present && !v.env.def.exists {
+		return
+	}
+	for f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return
+		}
+		f = f.Elem()
+	}
+	if err = bound(f, v, "min", v.env.min); err != nil {
+		return
+	}
+	if err = bound(f, v, "max", v.env.max); err != nil {
+		return
+	}
+	if err = each(f, v, "oneof", v.env.oneof, oneof); err != nil {
+		return
+	}
+	return each(f, v, "pattern", v.env.pattern, pattern)
+}
+
+// bound checks min or max rule. Numbers and durations are compared by value, strings, slices and maps by length
+func bound(f reflect.Value, v field, rule string, param strTag) (err error) {
+	var less, greater bool
+	if !param.exists {
+		return
+	}
+	limit := strings.TrimSpace(param.value)
+	switch {
+	case f.Type() == durationType:
+		var d time.Duration
+		if d, err = time.ParseDuration(limit); err == nil {
+			less, greater = f.Int() < int64(d), f.Int() > int64(d)
+		}
+	case isInt(f.Kind()):
+		var i int64
+		if i, err = strconv.ParseInt(limit, 10, 64); err == nil {
+			less, greater = f.Int() < i, f.Int() > i
+		}
+	case isUint(f.Kind()):
+		var u uint64
+		if u, err = strconv.ParseUint(limit, 10, 64); err == nil {
+			less, greater = f.Uint() < u, f.Uint() > u
+		}
+	case f.Kind() == reflect.Float32 || f.Kind() == reflect.Float64:
+		var x float64
+		if x, err = strconv.ParseFloat(limit, 64); err == nil {
+			less, greater = f.Float() < x, f.Float() > x
+		}
+	case f.Kind() == reflect.String || f.Kind() == reflect.Slice || f.Kind() == reflect.Map:
+		var l int
+		if l, err = strconv.Atoi(limit); err == nil {
+			less, greater = f.Len() < l, f.Len() > l
+		}
+	default:
+		return v.tagError(param, "%s can't be applied to type %s", rule, f.Type())
+	}
+	if err != nil {
+		return v.tagError(param, "invalid %s=%s: %v", rule, limit, err)
+	}
+	if (rule == "min" && less) || (rule == "max" && greater) {
+		return &ValidationError{Field: v.path, Name: v.env.name, Rule: rule, Param: limit, Value: text(f)}
+	}
+	return
+}
+
+// each applies rule to the value or to every item of the slice
+func each(f reflect.Value, v field, rule string, param strTag, check func(string, string) (bool, error)) (err error) {
+	if !param.exists {
+		return
+	}
+	items := []reflect.Value{f}
+	if f.Kind() == reflect.Slice {
+		items = items[:0]
+		for i := 0; i < f.Len(); i++ {
+			items = append(items, f.Index(i))
+		}
+	}
+	for _, item := range items {
+		if !isText(item) {
+			return v.tagError(param, "%s can't be applied to type %s", rule, f.Type())
+		}
+		var ok bool
+		if ok, err = check(text(item), param.value); err != nil {
+			return v.tagError(param, "invalid %s=%s: %v", rule, param.value, err)
+		}
+		if !ok {
+			return &ValidationError{Field: v.path, Name: v.env.name, Rule: rule, Param: param.value, Value: text(item)}
+		}
+	}
+	return
+}
+
+// oneof returns true if s is one of values; e.g. [debug|info|warn]
+func oneof(s, values string) (bool, error) {
+	values = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(values), "["), "]")
+	for _, value := range strings.Split(values, "|") {
+		if strings.TrimSpace(value) == s {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// pattern returns true if s matches regular expression expr. Compiled expressions are cached
+func pattern(s, expr string) (bool, error) {
+	if r, found := patterns.Load(expr); found {
+		return r.(*regexp.Regexp).MatchString(s), nil
+	}
+	r, err := regexp.Compile(expr)
+	if err != nil {
+		return false, err
+	}
+	patterns.Store(expr, r)
+	return r.MatchString(s), nil
+}
+
+// isText returns true if value of f can be converted to text by text function
+func isText(f reflect.Value) bool {
+	k := f.Kind()
+	return k == reflect.String || k == reflect.Bool || isInt(k) || isUint(k) || k == reflect.Float32 ||
+		k == reflect.Float64
+}
+
+// text returns value of f formatted as string
+func text(f reflect.Value) string {
+	switch {
+	case f.Type() == durationType:
+		return time.Duration(f.Int()).String()
+	case f.Kind() == reflect.String:
+		return f.String()
+	case f.Kind() == reflect.Bool:
+		return strconv.FormatBool(f.Bool())
+	case isInt(f.Kind()):
+		return strconv.FormatInt(f.Int(), 10)
+	case isUint(f.Kind()):
+		return strconv.FormatUint(f.Uint(), 10)
+	case f.Kind() == reflect.Float32 || f.Kind() == reflect.Float64:
+		return strconv.FormatFloat(f.Float(), 'g', -1, f.Type().Bits())
+	}
+	return fmt.Sprint(f.Interface())
+}
+
+func isInt(k reflect.Kind) bool {
+	return k == reflect.Int || k == reflect.Int8 || k == reflect.Int16 || k == reflect.Int32 || k == reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k == reflect.Uint || k == reflect.Uint8 || k == reflect.Uint16 || k == reflect.Uint32 || k == reflect.Uint64
+}
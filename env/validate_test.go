@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package env
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validated struct {
+	Port     int               `env:"PORT, default=8080, min=1, max=65535"`
+	Workers  uint8             `env:"WORKERS, min=1"`
+	Ratio    float64           `env:"RATIO, min=0, max=1"`
+	Timeout  time.Duration     `env:"TIMEOUT, default=30s, min=1s, max=1m"`
+	Level    string            `env:"LEVEL, default=info, oneof=[debug|info|warn]"`
+	Name     string            `env:"NAME, min=3, max=8, pattern=^[a-z]+$"`
+	Regions  []string          `env:"REGIONS, min=1, oneof=[eu|us]"`
+	Codes    []int             `env:"CODES, oneof=[200|404]"`
+	Labels   map[string]string `env:"LABELS, max=2"`
+	Replicas *int              `env:"REPLICAS, min=1"`
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+	v := &validated{}
+	err := BindFrom(MapLookuper{"NAME": "orders", "REGIONS": "eu,us", "CODES": "404", "LABELS": "a:1"}, v)
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, v.Port)
+	assert.Equal(t, "info", v.Level)
+	assert.Nil(t, v.Replicas)
+
+	tests := []struct {
+		name   string
+		source MapLookuper
+		err    *ValidationError
+	}{
+		{"int below min", MapLookuper{"PORT": "0"},
+			&ValidationError{Field: "validated.Port", Name: "PORT", Rule: "min", Param: "1", Value: "0"}},
+		{"int above max", MapLookuper{"PORT": "70000"},
+			&ValidationError{Field: "validated.Port", Name: "PORT", Rule: "max", Param: "65535", Value: "70000"}},
+		{"uint below min", MapLookuper{"WORKERS": "0"},
+			&ValidationError{Field: "validated.Workers", Name: "WORKERS", Rule: "min", Param: "1", Value: "0"}},
+		{"float above max", MapLookuper{"RATIO": "1.5"},
+			&ValidationError{Field: "validated.Ratio", Name: "RATIO", Rule: "max", Param: "1", Value: "1.5"}},
+		{"duration above max", MapLookuper{"TIMEOUT": "2m"},
+			&ValidationError{Field: "validated.Timeout", Name: "TIMEOUT", Rule: "max", Param: "1m", Value: "2m0s"}},
+		{"oneof", MapLookuper{"LEVEL": "trace"},
+			&ValidationError{Field: "validated.Level", Name: "LEVEL", Rule: "oneof", Param: "[debug|info|warn]", Value: "trace"}},
+		{"string too short", MapLookuper{"NAME": "ab"},
+			&ValidationError{Field: "validated.Name", Name: "NAME", Rule: "min", Param: "3", Value: "ab"}},
+		{"string too long", MapLookuper{"NAME": "inventory"},
+			&ValidationError{Field: "validated.Name", Name: "NAME", Rule: "max", Param: "8", Value: "inventory"}},
+		{"pattern", MapLookuper{"NAME": "Orders"},
+			&ValidationError{Field: "validated.Name", Name: "NAME", Rule: "pattern", Param: "^[a-z]+$", Value: "Orders"}},
+		{"empty slice", MapLookuper{"REGIONS": ""},
+			&ValidationError{Field: "validated.Regions", Name: "REGIONS", Rule: "min", Param: "1", Value: "[]"}},
+		{"slice item", MapLookuper{"REGIONS": "eu,au"},
+			&ValidationError{Field: "validated.Regions", Name: "REGIONS", Rule: "oneof", Param: "[eu|us]", Value: "au"}},
+		{"numeric slice item", MapLookuper{"CODES": "200,500"},
+			&ValidationError{Field: "validated.Codes", Name: "CODES", Rule: "oneof", Param: "[200|404]", Value: "500"}},
+		{"map size", MapLookuper{"LABELS": "a:1,b:2,c:3"},
+			&ValidationError{Field: "validated.Labels", Name: "LABELS", Rule: "max", Param: "2", Value: "map[a:1 b:2 c:3]"}},
+		{"pointer", MapLookuper{"REPLICAS": "0"},
+			&ValidationError{Field: "validated.Replicas", Name: "REPLICAS", Rule: "min", Param: "1", Value: "0"}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var verr *ValidationError
+			err := BindFrom(test.source, &validated{})
+			assert.True(t, errors.As(err, &verr))
+			assert.Equal(t, test.err, verr)
+			assert.EqualError(t, err, test.err.Error())
+		})
+	}
+}
+
+func TestValidationOfDefault(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		Level string `env:"LEVEL, default=trace, oneof=[debug|info]"`
+	}
+	err := BindFrom(MapLookuper{}, &token{})
+	assert.EqualError(t, err, "LEVEL value 'trace' violates oneof=[debug|info]")
+}
+
+func TestInvalidValidationRule(t *testing.T) {
+	t.Parallel()
+	type token struct {
+		Port    int    `env:"PORT, default=1, min=one"`
+		Name    string `env:"NAME, default=x, pattern=^(x$"`
+		Enabled bool   `env:"ENABLED, default=true, min=1"`
+	}
+	var bindErr *BindError
+	err := BindFrom(MapLookuper{}, &token{})
+	assert.True(t, errors.As(err, &bindErr))
+	assert.Len(t, bindErr.Errors, 3)
+	assert.Contains(t, bindErr.Errors[0].Error(), "invalid tag of token.Port at column 18: invalid min=one")
+	assert.Contains(t, bindErr.Errors[1].Error(), "invalid tag of token.Name at column 18: invalid pattern=^(x$")
+	assert.EqualError(t, bindErr.Errors[2], "invalid tag of token.Enabled at column 24: min can't be applied to type bool")
+	var tagErr *TagError
+	assert.True(t, errors.As(bindErr.Errors[0], &tagErr))
+	assert.Equal(t, &TagError{Field: "token.Port", Tag: "PORT, default=1, min=one", Column: 18,
+		Message: `invalid min=one: strconv.ParseInt: parsing "one": invalid syntax`}, tagErr)
+}